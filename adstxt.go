@@ -1,7 +1,6 @@
 package adstxt
 
 import (
-	"bufio"
 	"bytes"
 	"fmt"
 	"runtime"
@@ -12,8 +11,11 @@ import (
 // Get crawl and parse Ads.txt file from remote host based on Ads.txt Specification Version 1.0.1
 // https://iabtechlab.com/wp-content/uploads/2017/09/IABOpenRTB_Ads.txt_Public_Spec_V1-0-1.pdf
 func Get(req *Request) (*Response, error) {
-	c := newCrawler()
+	return NewCrawler().Get(req)
+}
 
+// Get crawl and parse Ads.txt file from remote host, using the crawler parse options
+func (c *Crawler) Get(req *Request) (*Response, error) {
 	// send Ads.txt request to remote server and parse response
 	for {
 		res, err := c.sendRequest(req)
@@ -32,18 +34,26 @@ func Get(req *Request) (*Response, error) {
 				return nil, err
 			}
 			req.URL = redirect
+		// the server response indicates Ads.txt file does not exist (HTTP Status Code 404): any seller may be authorized
+		case res.StatusCode == 404:
+			return &Response{
+				Request:       req,
+				Records:       newRecords(),
+				Expires:       time.Now().UTC().AddDate(0, 0, 7),
+				Authorization: AuthorizationNoFile,
+			}, nil
 		// client error in remote server response
 		case 400 <= res.StatusCode && res.StatusCode < 500:
 			return nil, fmt.Errorf(errHTTPClientError, res.Status, req.Domain, req.URL)
 		// the server response indicates Success (HTTP Status Code 200): read and parse the content of the Ads.txt file
 		case res.StatusCode == 200:
-			body, err := c.readBody(req, res)
-			if err != nil {
+			if err := c.checkContentType(req, res); err != nil {
 				return nil, err
 			}
 
-			// return new resposne
-			records, err := ParseBody(body)
+			// parse Ads.txt file while reading the response body
+			// subdomain variables are validated to be within the requested root domain
+			records, err := ParseReaderWithOptions(res.Body, c.Options.withDomain(req.Domain))
 			if err != nil {
 				return nil, err
 			}
@@ -53,7 +63,8 @@ func Get(req *Request) (*Response, error) {
 				Request: req,
 				Records: records,
 				// Ads.txt file default expiration date is set to 7 days (secion 3.6 EXPIRATION of IAB Ads.txt specification)
-				Expires: time.Now().UTC().AddDate(0, 0, 7),
+				Expires:       time.Now().UTC().AddDate(0, 0, 7),
+				Authorization: records.AuthorizationState(),
 			}
 
 			// parse Ads.txt expiration date from response (else default expiration time is used)
@@ -73,6 +84,11 @@ func Get(req *Request) (*Response, error) {
 // GetMultiple crawl and parse multiple Ads.txt files from remote hosts based on Ads.txt Specification Version 1.0.1
 // https://iabtechlab.com/wp-content/uploads/2017/09/IABOpenRTB_Ads.txt_Public_Spec_V1-0-1.pdf
 func GetMultiple(req []*Request, h Handler) {
+	NewCrawler().GetMultiple(req, h)
+}
+
+// GetMultiple crawl and parse multiple Ads.txt files from remote hosts, using the crawler parse options
+func (c *Crawler) GetMultiple(req []*Request, h Handler) {
 	// For faster crawling, use new goroutine for each request and set waitgroup to wait for all goroutine to finish
 	var wg sync.WaitGroup
 	wg.Add(len(req))
@@ -87,7 +103,7 @@ func GetMultiple(req []*Request, h Handler) {
 		guard <- struct{}{}
 		// crawl and parse request
 		go func(r *Request) {
-			res, err := Get(r)
+			res, err := c.Get(r)
 			h.Handle(r, res, err)
 			<-guard
 			defer wg.Done()
@@ -101,42 +117,11 @@ func GetMultiple(req []*Request, h Handler) {
 // ParseBody parse Ads.txt file based on Ads.txt Specification Version 1.0.1
 // https://iabtechlab.com/wp-content/uploads/2017/09/IABOpenRTB_Ads.txt_Public_Spec_V1-0-1.pdf
 func ParseBody(b []byte) (*Records, error) {
-	// use custom split function to sunpport different end-of-line marker (CR, CRLF etc)
-	split := func(data []byte, atEOF bool) (advance int, token []byte, err error) {
-		if atEOF && len(data) == 0 {
-			return 0, nil, nil
-		}
-		if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
-			if data[i] == '\n' {
-				// We have a line terminated by single newline.
-				return i + 1, data[0:i], nil
-			}
-			advance = i + 1
-			if len(data) > i+1 && data[i+1] == '\n' {
-				advance++
-			}
-			return advance, data[0:i], nil
-		}
-		// If we're at EOF, we have a final, non-terminated line. Return it.
-		if atEOF {
-			return len(data), data, nil
-		}
-		// Request more data.
-		return 0, nil, nil
-	}
-
-	scanner := bufio.NewScanner(bytes.NewReader(b))
-	scanner.Split(split)
-
-	// loop over Ads.txt file lines and parse each line
-	lines := []string{}
-	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
+	return ParseBodyWithOptions(b, nil)
+}
 
-	return parseRecords(lines), nil
+// ParseBodyWithOptions parse Ads.txt file based on Ads.txt Specification Version 1.0.1, using the specified
+// parse options to control validation rules, strict mode and maximum line length
+func ParseBodyWithOptions(b []byte, opts *ParseOptions) (*Records, error) {
+	return ParseReaderWithOptions(bytes.NewReader(b), opts)
 }
@@ -1,9 +1,14 @@
 package adstxt
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -25,7 +30,7 @@ func TestGetMultiple(t *testing.T) {
 	}
 
 	// expected response
-	const expected = "greenadexchange.com,XF7342,DIRECT\nsubdomain=test.com"
+	const expected = "appnexus.com,7342,DIRECT\nsubdomain=test.com"
 
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		io.WriteString(w, expected)
@@ -45,7 +50,7 @@ func TestGetMultiple(t *testing.T) {
 // TestGet tesing fetch and parse Ads.txt file from remote host
 func TestGet(t *testing.T) {
 	// expected response
-	const expected = "greenadexchange.com,XF7342,DIRECT\nsubdomain=test.com"
+	const expected = "appnexus.com,7342,DIRECT\nsubdomain=test.com"
 
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		io.WriteString(w, expected)
@@ -72,6 +77,10 @@ func TestGet(t *testing.T) {
 	if len(res.Variables) != 1 {
 		t.Errorf("Expected single Variable record but found [%d]", len(res.Variables))
 	}
+
+	if res.Authorization != AuthorizationHasSellers {
+		t.Errorf("Expected authorization state to be [%s] but recieved [%s]", AuthorizationHasSellers, res.Authorization)
+	}
 }
 
 // TestParseBody test paring []byte array into []Line array
@@ -120,7 +129,7 @@ func TestParseBody(t *testing.T) {
 // TestParseRecordsSuccess test parsing to valid Ads.txt file
 func TestParseRecordsSuccess(t *testing.T) {
 	b := []byte("greenadexchange.com,XF7342,  	DIRECT\ngreenadexchange.com, XF7342, DIRECT, 5jyxf8k54\n#greenadexchange.com,XF7342,DIRECT\nsubdomain=dev.example.com")
-	res, err := ParseBody(b)
+	res, err := ParseBodyWithOptions(b, &ParseOptions{Registry: testRegistry})
 
 	if err != nil {
 		t.Errorf("Expected no errors [%s]", err.Error())
@@ -139,10 +148,106 @@ func TestParseRecordsSuccess(t *testing.T) {
 	}
 }
 
+// TestParseRecordsWithExtension test parsing Ads.txt file with data record extension data
+func TestParseRecordsWithExtension(t *testing.T) {
+	b := []byte("greenadexchange.com, pub-1, DIRECT, f08c47fec0942fa0; extra=1\ngreenadexchange.com, pub-2, RESELLER; extra=2 # comment")
+	res, err := ParseBodyWithOptions(b, &ParseOptions{Registry: testRegistry})
+
+	if err != nil {
+		t.Errorf("Expected no errors [%s]", err.Error())
+	}
+
+	if len(res.Warnings) > 0 {
+		t.Errorf("Expected no warning when parsing lines, but recieved [%d] warnings", len(res.Warnings))
+	}
+
+	if len(res.DataRecords) != 2 {
+		t.Fatalf("Failed to parse Ads.txt records, expected number of records to be 2 and not [%d]", len(res.DataRecords))
+	}
+
+	if res.DataRecords[0].Extensions != "extra=1" || res.DataRecords[1].Extensions != "extra=2" {
+		t.Errorf("Expected Extensions to be parsed but recieved [%s] [%s]", res.DataRecords[0].Extensions, res.DataRecords[1].Extensions)
+	}
+}
+
+// TestParseRecordsWithKeyValueExtension test parsing data record with extension data holding list of <KEY>=<VALUE> pairs
+func TestParseRecordsWithKeyValueExtension(t *testing.T) {
+	b := []byte("greenadexchange.com, pub-0000000000000000, DIRECT, f08c47fec0942fa0; a=1;b=2;c=3;d=4;e=5;f=6")
+	res, err := ParseBodyWithOptions(b, &ParseOptions{Registry: testRegistry})
+
+	if err != nil {
+		t.Errorf("Expected no errors [%s]", err.Error())
+	}
+
+	if len(res.Warnings) > 0 {
+		t.Errorf("Expected no warning when parsing lines, but recieved [%d] warnings [%s]", len(res.Warnings), res.Warnings[0].Code)
+	}
+
+	if len(res.DataRecords) != 1 || res.DataRecords[0].Extensions != "a=1;b=2;c=3;d=4;e=5;f=6" {
+		t.Errorf("Expected data record with Extensions [%s] but recieved [%v]", "a=1;b=2;c=3;d=4;e=5;f=6", res.DataRecords)
+	}
+}
+
+// TestParseRecordsWarningColumn test warning column span is relative to the original Ads.txt line
+func TestParseRecordsWarningColumn(t *testing.T) {
+	b := []byte("  greenadexchange.com, XF7342, unknown # comment")
+	res, err := ParseBody(b)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(res.Warnings) != 1 {
+		t.Fatalf("Expected single warning when parsing Ads.txt but recieved [%d] warnings", len(res.Warnings))
+	}
+
+	w := res.Warnings[0]
+	if w.Column != 32 || w.EndColumn != 39 {
+		t.Errorf("Expected warning to concern columns [32-39] but recieved columns [%d-%d]", w.Column, w.EndColumn)
+	}
+	if string(b)[w.Column-1:w.EndColumn-1] != "unknown" {
+		t.Errorf("Expected warning column span to point to [unknown] but points to [%s]", string(b)[w.Column-1:w.EndColumn-1])
+	}
+}
+
+// TestParseRecordsProvenance test parsed records keep their line number, raw line text and comment
+func TestParseRecordsProvenance(t *testing.T) {
+	body := []string{
+		"# Ads.txt file",
+		"greenadexchange.com, XF7342, DIRECT # video inventory",
+		"",
+		"subdomain=dev.example.com",
+	}
+	res, err := ParseBodyWithOptions([]byte(strings.Join(body, "\r\n")), &ParseOptions{Registry: testRegistry})
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(res.DataRecords) != 1 || len(res.Variables) != 1 {
+		t.Fatalf("Expected single DataRecord and single Variable but recieved [%d] [%d]", len(res.DataRecords), len(res.Variables))
+	}
+
+	r := res.DataRecords[0]
+	if r.Line != 2 || r.Raw != body[1] || r.Comment != "video inventory" {
+		t.Errorf("Expected DataRecord provenance to be line #2 [%s] [video inventory] but recieved line #%d [%s] [%s]", body[1], r.Line, r.Raw, r.Comment)
+	}
+
+	v := res.Variables[0]
+	if v.Line != 4 || v.Raw != body[3] || v.Comment != "" {
+		t.Errorf("Expected Variable provenance to be line #4 [%s] but recieved line #%d [%s] [%s]", body[3], v.Line, v.Raw, v.Comment)
+	}
+
+	j, _ := json.Marshal(r)
+	if string(j) != "{\"adverterdomain\":\"greenadexchange.com\",\"publisheraccountid\":\"XF7342\",\"accountype\":\"DIRECT\",\"line\":2,\"raw\":\"greenadexchange.com, XF7342, DIRECT # video inventory\",\"comment\":\"video inventory\"}" {
+		t.Errorf("Json encoded DataRecord is different than expected [%s]", string(j))
+	}
+}
+
 // TestParseRecordsFailure test parsing to invalid Ads.txt file
 func TestParseRecordsFailure(t *testing.T) {
 	b1 := []byte("greenadexchange.com,XF7342,\ngreenadexchange.com, XF7342, DIRECT, 5jyxf8k54\n#greenadexchange.com,XF7342,DIRECT\nsubdomain=dev.example.com")
-	res, err := ParseBody(b1)
+	res, err := ParseBodyWithOptions(b1, &ParseOptions{Registry: testRegistry})
 
 	if err != nil {
 		t.Error(err)
@@ -156,8 +261,12 @@ func TestParseRecordsFailure(t *testing.T) {
 		t.Error("Expected warning message to indicate account type is missing")
 	}
 
+	if res.Warnings[0].Code != CodeMissingAccountType || res.Warnings[0].Field != 3 {
+		t.Errorf("Expected warning code to be [%s] for field #3 but recieved [%s] for field #%d", CodeMissingAccountType, res.Warnings[0].Code, res.Warnings[0].Field)
+	}
+
 	b2 := []byte("###this is a comment\ngreenadexchange.com, XF7342, DIRECT, 5jyxf8k54\n#greenadexchange.com,XF7342,DIRECT\nsubdomains=dev.example.com")
-	res, err = ParseBody(b2)
+	res, err = ParseBodyWithOptions(b2, &ParseOptions{Registry: testRegistry})
 
 	if len(res.Warnings) == 0 {
 		t.Error("Expected warnings when parsing Ads.txt with invalid Variable type line")
@@ -172,3 +281,232 @@ func TestParseRecordsFailure(t *testing.T) {
 	}
 
 }
+
+// TestGetNotFound test fetch Ads.txt file which does not exist on remote host
+func TestGetNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	req, _ := NewRequest(ts.URL)
+	res, err := Get(req)
+	if err != nil {
+		t.Fatalf("Expected no error when Ads.txt file does not exist but recieved [%s]", err)
+	}
+	if res.Authorization != AuthorizationNoFile || len(res.DataRecords) != 0 || len(res.Warnings) != 0 {
+		t.Errorf("Expected empty response with [%s] authorization state but recieved [%s]", AuthorizationNoFile, res.Authorization)
+	}
+
+	// other client errors are still reported
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer ts.Close()
+
+	req, _ = NewRequest(ts.URL)
+	if _, err := Get(req); err == nil {
+		t.Errorf("Expected error when remote host responds with [%d]", http.StatusForbidden)
+	}
+}
+
+// TestParseBodyDiscardBody test parsing Ads.txt file without keeping the original file content
+func TestParseBodyDiscardBody(t *testing.T) {
+	b := []byte("greenadexchange.com,XF7342,DIRECT\nsubdomain=test.com")
+
+	res, err := ParseBodyWithOptions(b, &ParseOptions{Registry: testRegistry, DiscardBody: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Body) != 0 {
+		t.Errorf("Expected Ads.txt file content to be discarded but recieved [%d] lines", len(res.Body))
+	}
+	if len(res.DataRecords) != 1 || len(res.Variables) != 1 || res.DataRecords[0].Raw != "greenadexchange.com,XF7342,DIRECT" {
+		t.Errorf("Expected records to be parsed when Ads.txt file content is discarded")
+	}
+}
+
+// quirkyFiles return the content of real-world quirky Ads.txt files found in testdata/quirky, used as seed corpus
+// of the fuzz targets and by the property tests
+func quirkyFiles(tb testing.TB) map[string][]byte {
+	paths, err := filepath.Glob(filepath.Join("testdata", "quirky", "*.txt"))
+	if err != nil || len(paths) == 0 {
+		tb.Fatalf("Expected quirky Ads.txt files in testdata/quirky [%v]", err)
+	}
+
+	files := map[string][]byte{}
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		files[filepath.Base(path)] = b
+	}
+	return files
+}
+
+// checkRecordsInvariants check the invariants of Ads.txt file parsed records: every line with content (other than
+// comment) yields a record, a variable or a warning, all line indexes are within the file range, and the records
+// round-trip through serialization
+func checkRecordsInvariants(t *testing.T, b []byte, rec *Records) {
+	lines := len(rec.Body)
+	yields := map[int]bool{}
+	inRange := func(what string, index int) {
+		if index < 1 || index > lines {
+			t.Errorf("Expected %s line index [%d] to be within [1, %d]", what, index, lines)
+		}
+	}
+
+	for _, r := range rec.DataRecords {
+		inRange("data record", r.Line)
+		yields[r.Line] = true
+	}
+	for _, v := range rec.Variables {
+		inRange("variable", v.Line)
+		yields[v.Line] = true
+	}
+	for _, w := range append(rec.Warnings[:len(rec.Warnings):len(rec.Warnings)], rec.Suppressed...) {
+		// file level warnings which do not concern specific lines (e.g. no data records) have no line index
+		if w.Index != 0 {
+			inRange("warning", w.Index)
+		}
+		for _, index := range w.Indexes {
+			inRange("warning", index)
+		}
+		yields[w.Index] = true
+	}
+
+	for i, l := range rec.Body {
+		if content, _ := splitComment(l); len(content) > 0 && content != commentDenote && !yields[i+1] {
+			t.Errorf("Expected line [%d] [%q] to yield a record, a variable or a warning", i+1, l)
+		}
+	}
+
+	// document round-trip: Ads.txt file is reproduced byte for byte
+	if d := ParseDocument(b); !bytes.Equal(d.Bytes(), b) {
+		t.Errorf("Expected document of [%q] to be serialized back to the same content", b)
+	}
+
+	// records round-trip: serialized records are parsed back to the same records (records which can not be
+	// serialized are rejected by Marshal). Comment lines are not serialized, unknown ad systems kept by suppression
+	// directives declared on comment lines are kept using the parse options
+	out, err := Marshal(rec, nil)
+	if err != nil {
+		return
+	}
+	again, err := ParseBodyWithOptions(out, &ParseOptions{IncludeSuppressed: true, KeepUnknownAdSystems: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again.DataRecords) != len(rec.DataRecords) || len(again.Variables) != len(rec.Variables) {
+		t.Fatalf("Expected [%q] to be parsed back into [%d] data records and [%d] variables but recieved [%d] and [%d]",
+			out, len(rec.DataRecords), len(rec.Variables), len(again.DataRecords), len(again.Variables))
+	}
+	for i, r := range rec.DataRecords {
+		if a := again.DataRecords[i]; a.String() != r.String() {
+			t.Errorf("Expected data record [%s] to round-trip but recieved [%s]", r.String(), a.String())
+		}
+	}
+	// white spaces surrounding variable value are not significant
+	for i, v := range rec.Variables {
+		if a := again.Variables[i]; a.Type != v.Type || strings.TrimSpace(a.Value) != strings.TrimSpace(v.Value) {
+			t.Errorf("Expected variable [%s] to round-trip but recieved [%s]", v.String(), a.String())
+		}
+	}
+}
+
+// TestParseBodyInvariants test the parsed records invariants on real-world quirky Ads.txt files
+func TestParseBodyInvariants(t *testing.T) {
+	for name, b := range quirkyFiles(t) {
+		t.Run(name, func(t *testing.T) {
+			rec, err := ParseBodyWithOptions(b, &ParseOptions{IncludeSuppressed: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkRecordsInvariants(t, b, rec)
+		})
+	}
+}
+
+// FuzzParseBody fuzz parsing Ads.txt file, checking the parsed records invariants
+func FuzzParseBody(f *testing.F) {
+	for _, b := range quirkyFiles(f) {
+		f.Add(b)
+	}
+	f.Add([]byte("greenadexchange.com,XF7342,DIRECT\nsubdomain=test.com"))
+	f.Add([]byte(""))
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		rec, err := ParseBodyWithOptions(b, &ParseOptions{IncludeSuppressed: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkRecordsInvariants(t, b, rec)
+	})
+}
+
+// benchmarkBody realistic Ads.txt file used by the parse benchmarks: thousands of data records of known ad systems
+// (most of them with certification authority ID), comments, variables and some invalid lines
+var benchmarkBody = func() []byte {
+	var b bytes.Buffer
+	b.WriteString("# Ads.txt file for example.com\n# adstxt:ignore-file ADSTXT_DUPLICATE_RECORD\n\n")
+	adSystems := DefaultRegistry().AdSystems()
+	for i := 0; i < 5000; i++ {
+		a := adSystems[i%len(adSystems)]
+		domain := "unknown-exchange.com"
+		switch {
+		case len(a.CanonicalDomains) > 0:
+			domain = a.CanonicalDomains[0]
+		case len(a.Domains) > 0 && !strings.Contains(a.Domains[0], " "):
+			domain = a.Domains[0]
+		}
+
+		switch {
+		case a.ID == 8:
+			fmt.Fprintf(&b, "google.com, pub-%016d, DIRECT, f08c47fec0942fa0 # AdSense\n", i)
+		case i%100 == 0:
+			fmt.Fprintf(&b, "%s, %d, DIRECTT\n", domain, i)
+		case i%50 == 0:
+			fmt.Fprintf(&b, "# %s\n", domain)
+		case len(a.CertAuthorityIDs) > 0:
+			fmt.Fprintf(&b, "%s, %d, RESELLER, %s\n", domain, i, a.CertAuthorityIDs[0])
+		default:
+			fmt.Fprintf(&b, "%s, %d, DIRECT, 5jyxf8k54\n", domain, i)
+		}
+	}
+	b.WriteString("contact=adops@example.com\nsubdomain=dev.example.com\nownerdomain=example.com\n")
+	return b.Bytes()
+}()
+
+// BenchmarkParseBody benchmark parsing large Ads.txt file
+func BenchmarkParseBody(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchmarkBody)))
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseBody(benchmarkBody); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkParseBodyDiscardBody benchmark parsing large Ads.txt file without keeping its content
+func BenchmarkParseBodyDiscardBody(b *testing.B) {
+	opts := &ParseOptions{DiscardBody: true}
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchmarkBody)))
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseBodyWithOptions(benchmarkBody, opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkReader benchmark reading large Ads.txt file line by line
+func BenchmarkReader(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchmarkBody)))
+	for i := 0; i < b.N; i++ {
+		rd := NewReader(bytes.NewReader(benchmarkBody), nil)
+		for rd.Next() {
+		}
+		rd.Finish()
+	}
+}
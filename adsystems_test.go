@@ -1,19 +1,33 @@
 package adstxt
 
 import (
-	"os"
 	"testing"
 )
 
-func TestMain(m *testing.M) {
-	// setup: add test values
-	adSystems[10000] = newAdSystem(10000, "greenadexchange", "greenadexchange.com")
-	adSystemDomains["greenadexchange.com"] = newAdSystemDomain("greenadexchange.com", 10000)
+// testRegistry registry of known ad systems used by tests: default registry, in addition to test ad systems
+var testRegistry = newTestRegistry()
 
-	adSystems[10001] = newAdSystem(10001, "testexchange", "testexchange.net")
-	adSystemDomains["testexchange.com"] = newAdSystemDomain("testexchange.com", 10001)
+// newTestRegistry create registry of known ad systems used by tests
+func newTestRegistry() *Registry {
+	adSystems := append(DefaultRegistry().AdSystems(),
+		&AdSystem{ID: 10000, Name: "greenadexchange", CanonicalDomains: []string{"greenadexchange.com"}},
+		&AdSystem{ID: 10001, Name: "testexchange", CanonicalDomains: []string{"testexchange.net"}})
 
-	os.Exit(m.Run())
+	domains := []*AdSystemDomain{
+		{Domain: "greenadexchange.com", ID: 10000},
+		{Domain: "testexchange.com", ID: 10001},
+	}
+	for _, a := range DefaultRegistry().AdSystems() {
+		for _, d := range a.Domains {
+			domains = append(domains, &AdSystemDomain{Domain: d, ID: a.ID})
+		}
+	}
+
+	r, err := NewRegistry(adSystems, domains)
+	if err != nil {
+		panic(err)
+	}
+	return r
 }
 
 // TestValidateDomain test ads.txt validate domain helper function
@@ -56,6 +70,13 @@ func TestRootDomain(t *testing.T) {
 		"http://abc.raisingourkids.com/": "raisingourkids.com",
 		"https://testme.tumblr.com/":     "tumblr.com",
 		"http://port.com:8080/grid":      "port.com",
+		"http://user@www.port.com:8080/": "port.com",
+		"www.example.com?query":          "example.com",
+		"http://www.example.co.uk/":      "example.co.uk",
+		"bucket.s3.amazonaws.com":        "bucket.s3.amazonaws.com",
+		"http://127.0.0.1:8080/ads.txt":  "127.0.0.1",
+		"http://[::1]:8080/ads.txt":      "::1",
+		"2001:db8::1":                    "2001:db8::1",
 	}
 
 	for k, v := range domains {
@@ -80,7 +101,7 @@ func TestAdSystemCanonicalName(t *testing.T) {
 	}
 
 	for _, d := range domains {
-		err := vaidateAdSystemCName(d)
+		err := testRegistry.validate(d)
 		if err != nil {
 			t.Error(err)
 		}
@@ -95,7 +116,7 @@ func TestAdSystemCanonicalName(t *testing.T) {
 	}
 
 	for _, d := range domains {
-		err := vaidateAdSystemCName(d)
+		err := testRegistry.validate(d)
 		if err == nil {
 			t.Errorf("%s is a valid AdSystem canonical name", d)
 		}
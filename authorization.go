@@ -0,0 +1,94 @@
+package adstxt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Ads.txt placeholder record (Ads.txt Specification Version 1.1): declared by publishers which do not authorize any
+// advertising system to sell their inventory
+const (
+	placeholderDomain    = "placeholder.example.com"
+	placeholderAccountID = "placeholder"
+	placeholderCertID    = "placeholder"
+)
+
+// AuthorizationState of Ads.txt file: which sellers are authorized by the publisher. Ads.txt Specification Version
+// 1.1 distinguishes a missing Ads.txt file (any seller may be authorized) from an Ads.txt file authorizing no seller
+type AuthorizationState int
+
+const (
+	// ignore first value by assigning to blank identifier
+	_ = iota
+	// AuthorizationNoFile Ads.txt file does not exist (HTTP 404 response)
+	AuthorizationNoFile AuthorizationState = iota
+	// AuthorizationEmpty Ads.txt file exists but has no valid data records
+	AuthorizationEmpty
+	// AuthorizationPlaceholderOnly Ads.txt file declares only the placeholder record: no seller is authorized
+	AuthorizationPlaceholderOnly
+	// AuthorizationHasSellers Ads.txt file authorizes at least one seller
+	AuthorizationHasSellers
+)
+
+// authorizationStateNames authorization states names, used when encoding authorization state to JSON
+var authorizationStateNames = map[AuthorizationState]string{
+	AuthorizationNoFile:          "nofile",
+	AuthorizationEmpty:           "empty",
+	AuthorizationPlaceholderOnly: "placeholderonly",
+	AuthorizationHasSellers:      "hassellers",
+}
+
+// custom "toString" method
+func (s AuthorizationState) String() string {
+	if name, ok := authorizationStateNames[s]; ok {
+		return name
+	}
+	return strconv.Itoa(int(s))
+}
+
+// MarshalText encode authorization state as its name (e.g. "hassellers")
+func (s AuthorizationState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decode authorization state from its name (case insensitive) or its numeric value
+func (s *AuthorizationState) UnmarshalText(b []byte) error {
+	text := strings.ToLower(strings.TrimSpace(string(b)))
+	for state, name := range authorizationStateNames {
+		if name == text {
+			*s = state
+			return nil
+		}
+	}
+
+	state, err := strconv.Atoi(text)
+	if err != nil {
+		return fmt.Errorf("[%s] is not a valid authorization state", string(b))
+	}
+	*s = AuthorizationState(state)
+	return nil
+}
+
+// IsPlaceholder check if data record is the placeholder record declared by publishers which do not authorize any
+// advertising system: placeholder.example.com, placeholder, DIRECT, placeholder
+func (r *DataRecord) IsPlaceholder() bool {
+	return normalizeDomain(r.AdverterDomain) == placeholderDomain &&
+		strings.EqualFold(r.PublisherAccountID, placeholderAccountID) &&
+		strings.EqualFold(r.AccountType, accountTypeDirect) &&
+		strings.EqualFold(r.CertAuthorityID, placeholderCertID)
+}
+
+// AuthorizationState return the authorization state of the parsed Ads.txt file: AuthorizationEmpty if the file has
+// no valid data records, AuthorizationPlaceholderOnly if all data records are the placeholder record, and
+// AuthorizationHasSellers otherwise
+func (r *Records) AuthorizationState() AuthorizationState {
+	state := AuthorizationEmpty
+	for _, dr := range r.DataRecords {
+		if !dr.IsPlaceholder() {
+			return AuthorizationHasSellers
+		}
+		state = AuthorizationPlaceholderOnly
+	}
+	return state
+}
@@ -0,0 +1,76 @@
+package adstxt
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestParsePlaceholder test parsing the placeholder record without validating it against the ad systems registry
+func TestParsePlaceholder(t *testing.T) {
+	rec, err := ParseBodyWithOptions([]byte("placeholder.example.com, placeholder, DIRECT, placeholder"), &ParseOptions{Registry: testRegistry})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.DataRecords) != 1 || !rec.DataRecords[0].IsPlaceholder() {
+		t.Fatalf("Expected placeholder record to be parsed but recieved [%d] data records", len(rec.DataRecords))
+	}
+	if len(rec.Warnings) != 0 {
+		t.Errorf("Expected placeholder record not to be flagged but recieved [%v]", rec.Warnings)
+	}
+
+	// placeholder record must be declared as is
+	tests := map[string]bool{
+		"placeholder.example.com, placeholder, DIRECT, placeholder":   true,
+		"Placeholder.Example.com., PLACEHOLDER, direct, Placeholder":  true,
+		"placeholder.example.com, placeholder, RESELLER, placeholder": false,
+		"placeholder.example.com, placeholder, DIRECT":                false,
+		"placeholder.example.com, 1234, DIRECT, placeholder":          false,
+		"placeholder.example.org, placeholder, DIRECT, placeholder":   false,
+	}
+	for line, placeholder := range tests {
+		r, _ := parseDataRecord(line)
+		if r.IsPlaceholder() != placeholder {
+			t.Errorf("Expected [%s] to be placeholder record [%t]", line, placeholder)
+		}
+	}
+}
+
+// TestRecordsAuthorizationState test Ads.txt file authorization state by its data records
+func TestRecordsAuthorizationState(t *testing.T) {
+	tests := map[string]AuthorizationState{
+		"": AuthorizationEmpty,
+		"# no sellers\ncontact=adops@example.com":                                                        AuthorizationEmpty,
+		"greenadexchange.com, XF7342, DIRECTT":                                                           AuthorizationEmpty,
+		"placeholder.example.com, placeholder, DIRECT, placeholder":                                      AuthorizationPlaceholderOnly,
+		"placeholder.example.com, placeholder, DIRECT, placeholder\ngreenadexchange.com, XF7342, DIRECT": AuthorizationHasSellers,
+		"greenadexchange.com, XF7342, DIRECT":                                                            AuthorizationHasSellers,
+	}
+
+	for body, state := range tests {
+		rec, _ := ParseBodyWithOptions([]byte(body), &ParseOptions{Registry: testRegistry})
+		if s := rec.AuthorizationState(); s != state {
+			t.Errorf("Expected [%q] authorization state to be [%s] but recieved [%s]", body, state, s)
+		}
+	}
+}
+
+// TestAuthorizationStateJSON test encoding and decoding authorization state by its name
+func TestAuthorizationStateJSON(t *testing.T) {
+	b, err := json.Marshal(&Response{Records: newRecords(), Authorization: AuthorizationPlaceholderOnly})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var res struct {
+		Authorization AuthorizationState `json:"authorization"`
+	}
+	if err := json.Unmarshal(b, &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Authorization != AuthorizationPlaceholderOnly {
+		t.Errorf("Expected authorization state to be decoded as [%s] but recieved [%s] from [%s]", AuthorizationPlaceholderOnly, res.Authorization, string(b))
+	}
+	if err := res.Authorization.UnmarshalText([]byte("unknown")); err == nil {
+		t.Errorf("Expected error when decoding unknown authorization state")
+	}
+}
@@ -2,7 +2,6 @@ package adstxt
 
 import (
 	"fmt"
-	"io/ioutil"
 	"log"
 	"net/http"
 	"net/url"
@@ -52,43 +51,58 @@ func readRedirects(s string) int {
 	return redirects[s]
 }
 
-// crawler provide methods for downloading Ads.txt files from remote host
-type crawler struct {
-	client    *http.Client // HTTP client used to make HTTP request for Ads.txt file from remote host
-	UserAgent string       // crawler UserAgent string
+// defaultClient HTTP client used by crawlers created without NewCrawler (e.g. zero value Crawler)
+var defaultClient = newHTTPClient()
+
+// Crawler provide methods for downloading Ads.txt files from remote host. The zero value Crawler is ready to use,
+// with the same settings as the crawler returned by NewCrawler
+type Crawler struct {
+	client    *http.Client    // HTTP client used to make HTTP request for Ads.txt file from remote host
+	UserAgent string          // crawler UserAgent string
+	Options   *ParseOptions   // Options parse options used to parse downloaded Ads.txt files (e.g. registry of known ad systems)
+	Resolver  *DomainResolver // Resolver resolve the root domain of redirect destinations (private suffix aware by default)
 }
 
-// NewCrawler Create new crawler to fetch Ads.txt file from remote host
-func newCrawler() *crawler {
-	return &crawler{
-		// Create client with required custom parameters.
-		// Options: Disable keep-alives, 30sec n/w call timeout, do not follow redirects by default
-		client: &http.Client{
-			CheckRedirect: func(req *http.Request, via []*http.Request) error {
-				return http.ErrUseLastResponse
-			},
-			Transport: &http.Transport{
-				DisableKeepAlives: true,
-			},
-			Timeout: time.Second * requestTimeout,
-		},
+// NewCrawler Create new crawler to fetch Ads.txt file from remote host, using its own HTTP client
+func NewCrawler() *Crawler {
+	return &Crawler{
+		client:    newHTTPClient(),
 		UserAgent: userAgent,
 	}
 }
 
+// newHTTPClient create HTTP client used to fetch Ads.txt files
+func newHTTPClient() *http.Client {
+	// Create client with required custom parameters.
+	// Options: Disable keep-alives, 30sec n/w call timeout, do not follow redirects by default
+	return &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+		Transport: &http.Transport{
+			DisableKeepAlives: true,
+		},
+		Timeout: time.Second * requestTimeout,
+	}
+}
+
 // send HTTP request to fetch Ads.txt file from remote host
-func (c *crawler) sendRequest(req *Request) (*http.Response, error) {
+func (c *Crawler) sendRequest(req *Request) (*http.Response, error) {
 	httpRequest, err := http.NewRequest("GET", req.URL, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	httpRequest.Header.Add("User-Agent", c.UserAgent)
+	ua := c.UserAgent
+	if len(ua) == 0 {
+		ua = userAgent
+	}
+	httpRequest.Header.Add("User-Agent", ua)
 	httpRequest.Header.Add("Accept", "text/plain")
 	httpRequest.Header.Add("Accept-Charset", "utf-8")
 	httpRequest.Header.Add("Content-Type", "text/plain; charset=utf-8")
 
-	res, err := c.client.Do(httpRequest)
+	res, err := c.httpClient().Do(httpRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -97,8 +111,8 @@ func (c *crawler) sendRequest(req *Request) (*http.Response, error) {
 }
 
 // handle HTTP redirect resonse: parse new redirect destination from HTTP response header
-func (c *crawler) handleRedirect(req *Request, res *http.Response) (string, error) {
-	redirect := res.Header.Get("Location")
+func (c *Crawler) handleRedirect(req *Request, res *http.Response) (string, error) {
+	redirect := normalizeURL(res.Header.Get("Location"))
 
 	// Returning error when redirect is happening to the same location
 	if redirect == req.URL {
@@ -110,29 +124,36 @@ func (c *crawler) handleRedirect(req *Request, res *http.Response) (string, erro
 
 	// Return error when the number of redirects for a single url are reaching a max
 	if readRedirects(redirect) > maxNumRedirects {
-		return "", fmt.Errorf(errInfiniteRedirect, req.Domain, req.URL, redirect)
+		return "", fmt.Errorf(errInfiniteRedirect, req.URL, redirect)
 	}
 
 	log.Printf("[%s]: redirect from [%s] to [%s]", res.Status, req.URL, redirect)
 
 	// Check if redirect destination has the same root domain as the reguest initial root doamin.
-	d, err := rootDomain(redirect)
+	d, err := c.resolver().RootDomain(redirect)
 	if err != nil {
 		return "", fmt.Errorf(errFailToParseRedirect, req.Domain, req.URL, redirect, err.Error())
 	}
 
+	// request root domain is resolved again using the crawler resolver, as the request may have been created using
+	// another resolver (e.g. NewRequest use the default resolver, while the crawler use ICANN-only resolver)
+	origin, err := c.resolver().RootDomain(req.Domain)
+	if err != nil {
+		origin = req.Domain
+	}
+
 	// According to IAB's ads.txt specification, section 3.1 "ACCESS METHOD":
 	// "If the server response indicates an HTTP/HTTPS redirect (301, 302, 307 status codes),
 	// the advertising system should follow the redirect and consume the data as authoritative for the source of the redirect,
 	// if and only if the redirect is within scope of the original root domain as defined above.
 	// Multiple redirects are valid as long as each redirect location remains within the original root domain."
-	if d != req.Domain {
+	if d != origin {
 		// If redirect to different domain, check that this is the first redirect to different domain
 		// According to IAB's ads.txt specification, section 3.1 "ACCESS METHOD":
 		// "Only a single HTTP redirect to a destination outside the original root domain is allowed to
 		// facilitate one-hop delegation of authority to a third party's web server domain."
-		prevDomain, _ := rootDomain(req.URL)
-		if prevDomain != req.Domain && prevDomain != d {
+		prevDomain, _ := c.resolver().RootDomain(req.URL)
+		if prevDomain != origin && prevDomain != d {
 			return "", fmt.Errorf(errRedirectToDifferentDomain, req.Domain, prevDomain, d)
 		}
 	}
@@ -161,26 +182,34 @@ func (c *crawler) handleRedirect(req *Request, res *http.Response) (string, erro
 	return redirect, nil
 }
 
-// Read HTTP response body
-func (c *crawler) readBody(req *Request, res *http.Response) ([]byte, error) {
-	// The HTTP Content-type should be ‘text/plain’, and all other Content-types should be treated as
-	// an error and the content ignored
-	contentType := res.Header.Get("Content-Type")
-	if strings.Index(contentType, "text/plain") != 0 {
-		return nil, fmt.Errorf(errHTTPBadContentType, req.URL, contentType)
+// httpClient return the HTTP client used by the crawler
+func (c *Crawler) httpClient() *http.Client {
+	if c.client == nil {
+		return defaultClient
 	}
+	return c.client
+}
 
-	// read response body
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
+// resolver return the domain resolver used by the crawler
+func (c *Crawler) resolver() *DomainResolver {
+	if c.Resolver == nil {
+		return defaultResolver
 	}
+	return c.Resolver
+}
 
-	return body, nil
+// check HTTP response content type: the HTTP Content-type should be ‘text/plain’, and all other Content-types should
+// be treated as an error and the content ignored
+func (c *Crawler) checkContentType(req *Request, res *http.Response) error {
+	contentType := res.Header.Get("Content-Type")
+	if strings.Index(contentType, "text/plain") != 0 {
+		return fmt.Errorf(errHTTPBadContentType, req.URL, contentType)
+	}
+	return nil
 }
 
 // parse Ads.txt file expiration date from the response Expires header
-func (c *crawler) parseExpires(res *http.Response) (time.Time, error) {
+func (c *Crawler) parseExpires(res *http.Response) (time.Time, error) {
 	expires := res.Header.Get("Expires")
 	if len(expires) == 0 {
 		return time.Time{}, fmt.Errorf("Failed to parse expires from response header")
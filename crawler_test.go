@@ -23,7 +23,7 @@ func TestSendAndParseRquest(t *testing.T) {
 	req, _ := NewRequest(ts.URL)
 
 	// test send request
-	c := newCrawler()
+	c := NewCrawler()
 	res, err := c.sendRequest(req)
 	if err != nil {
 		t.Error(err)
@@ -31,7 +31,11 @@ func TestSendAndParseRquest(t *testing.T) {
 
 	defer res.Body.Close()
 
-	body, err := c.readBody(req, res)
+	if err := c.checkContentType(req, res); err != nil {
+		t.Error(err)
+	}
+
+	body, err := io.ReadAll(res.Body)
 	if err != nil {
 		t.Error(err)
 	}
@@ -41,6 +45,27 @@ func TestSendAndParseRquest(t *testing.T) {
 	}
 }
 
+// TestZeroValueCrawler test fetching Ads.txt file using zero value crawler
+func TestZeroValueCrawler(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.UserAgent() != userAgent {
+			t.Errorf("Expected zero value crawler to send default UserAgent but recieved [%s]", r.UserAgent())
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "appnexus.com,7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	req, _ := NewRequest(ts.URL)
+	res, err := (&Crawler{}).Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.DataRecords) != 1 {
+		t.Errorf("Expected single DataRecord but recieved [%d]", len(res.DataRecords))
+	}
+}
+
 // TestHandleRedirect test crawler handle HTTP redirect response: extract new redirect destination from HTTP resposne
 func TestHandleRedirect(t *testing.T) {
 	const redirect = "http://gotest.com/ads.txt"
@@ -55,7 +80,7 @@ func TestHandleRedirect(t *testing.T) {
 	req, _ := NewRequest(ts.URL)
 
 	// test send request
-	c := newCrawler()
+	c := NewCrawler()
 	res, err := c.sendRequest(req)
 	if err != nil {
 		t.Error(err)
@@ -74,6 +99,33 @@ func TestHandleRedirect(t *testing.T) {
 	}
 }
 
+// TestHandleRedirectResolver test redirect destinations are checked against the request root domain resolved using
+// the crawler resolver, even if the request was created using another resolver
+func TestHandleRedirectResolver(t *testing.T) {
+	// private suffix aware root domain [bucket.s3.amazonaws.com], ICANN-only root domain [amazonaws.com]
+	req, _ := NewRequest("http://bucket.s3.amazonaws.com/ads.txt")
+	c := &Crawler{Resolver: &DomainResolver{ICANNOnly: true}}
+
+	redirects := []string{"https://bucket.s3.amazonaws.com/ads.txt", "https://thirdparty.com/ads.txt"}
+	for _, redirect := range redirects {
+		res := &http.Response{Status: "301 Moved Permanently", StatusCode: http.StatusMovedPermanently,
+			Header: http.Header{"Location": []string{redirect}}}
+
+		r, err := c.handleRedirect(req, res)
+		if err != nil {
+			t.Fatalf("Expected redirect from [%s] to [%s] to be followed but recieved [%s]", req.URL, redirect, err)
+		}
+		req.URL = r
+	}
+
+	// second redirect out of the original root domain is not allowed
+	res := &http.Response{Status: "301 Moved Permanently", StatusCode: http.StatusMovedPermanently,
+		Header: http.Header{"Location": []string{"https://another.com/ads.txt"}}}
+	if _, err := c.handleRedirect(req, res); err == nil {
+		t.Errorf("Expected error when redirecting from [%s] to another root domain", req.URL)
+	}
+}
+
 // TestParseExpires test parse Ads.txt file expires from HTTP response Header
 func TestParseExpires(t *testing.T) {
 	// expected response
@@ -89,7 +141,7 @@ func TestParseExpires(t *testing.T) {
 	req, _ := NewRequest(ts.URL)
 
 	// test send request
-	c := newCrawler()
+	c := NewCrawler()
 	res, err := c.sendRequest(req)
 	if err != nil {
 		t.Error(err)
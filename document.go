@@ -0,0 +1,330 @@
+package adstxt
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	errEditDataRecord = "Data record [%s] can not be written to Ads.txt document: %s"
+	errEditVariable   = "Variable [%s] can not be written to Ads.txt document: %s"
+)
+
+// LineKind type of a single Ads.txt document line
+type LineKind int
+
+const (
+	// BlankLine empty line, or line with white spaces only
+	BlankLine LineKind = iota
+	// CommentLine line with comment only
+	CommentLine
+	// DataRecordLine line declaring data record
+	DataRecordLine
+	// VariableLine line declaring variable
+	VariableLine
+	// UnknownLine line which could not be parsed into data record or variable
+	UnknownLine
+)
+
+// DocumentLine single line of Ads.txt document
+type DocumentLine struct {
+	Kind       LineKind    // Kind type of the line
+	Raw        string      // Raw text of the line, without the end-of-line marker
+	EOL        string      // EOL end-of-line marker of the line ("\n", "\r\n", "\r" or empty for the last line of the file)
+	DataRecord *DataRecord // DataRecord declared on the line (DataRecordLine only)
+	Variable   *Variable   // Variable declared on the line (VariableLine only)
+}
+
+// Document holds all lines of Ads.txt file, and allows to edit Ads.txt file while preserving its original layout:
+// lines which are not edited are serialized back byte-identical
+type Document struct {
+	Lines []*DocumentLine
+}
+
+// ParseDocument parse Ads.txt file into Ads.txt document. Lines are parsed into data records and variables based on
+// their syntax only: validation rules are not applied, and lines which could not be parsed are kept as UnknownLine
+func ParseDocument(b []byte) *Document {
+	d := &Document{Lines: []*DocumentLine{}}
+	p := &parser{registry: defaultRegistry, suppress: newSuppressions(false), extensions: newExtensionParserSet(defaultRegistry, nil)}
+
+	for _, raw := range splitLinesEOL(string(b)) {
+		txt := strings.TrimRight(raw, "\r\n")
+		dl := &DocumentLine{Raw: txt, EOL: raw[len(txt):]}
+
+		l, _ := p.parseLine(len(d.Lines)+1, txt)
+		switch {
+		case l == nil && len(strings.TrimSpace(txt)) == 0:
+			dl.Kind = BlankLine
+		case l == nil:
+			dl.Kind = CommentLine
+		case l.DataRecord != nil:
+			dl.Kind, dl.DataRecord = DataRecordLine, l.DataRecord
+		case l.Variable != nil:
+			dl.Kind, dl.Variable = VariableLine, l.Variable
+		default:
+			dl.Kind = UnknownLine
+		}
+
+		d.Lines = append(d.Lines, dl)
+	}
+
+	return d
+}
+
+// Bytes serialize Ads.txt document back into Ads.txt file content
+func (d *Document) Bytes() []byte {
+	var b bytes.Buffer
+	d.WriteTo(&b)
+	return b.Bytes()
+}
+
+// WriteTo write Ads.txt document into w. WriteTo is the io.WriterTo interface implementation for the Document type
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var n int64
+	for _, l := range d.Lines {
+		c, err := io.WriteString(w, l.Raw+l.EOL)
+		n += int64(c)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// DataRecords return all data records declared in Ads.txt document
+func (d *Document) DataRecords() []*DataRecord {
+	records := []*DataRecord{}
+	for _, l := range d.Lines {
+		if l.Kind == DataRecordLine {
+			records = append(records, l.DataRecord)
+		}
+	}
+	return records
+}
+
+// Variables return all variables declared in Ads.txt document
+func (d *Document) Variables() []*Variable {
+	variables := []*Variable{}
+	for _, l := range d.Lines {
+		if l.Kind == VariableLine {
+			variables = append(variables, l.Variable)
+		}
+	}
+	return variables
+}
+
+// AddRecord add new data record to Ads.txt document, after the last data record of the document (or before the
+// first variable, if the document has no data records). Data record fields are trimmed and account type is uppercased,
+// as they are parsed back. Returns error if the data record can not be written as a single line parsed back into the
+// same fields (e.g. field containing "," or "#")
+func (d *Document) AddRecord(r *DataRecord) error {
+	if err := validateDataRecordText(r); err != nil {
+		return fmt.Errorf(errEditDataRecord, r.AdverterDomain, err.Error())
+	}
+
+	index := len(d.Lines)
+	for i, l := range d.Lines {
+		if l.Kind == DataRecordLine {
+			index = i + 1
+		} else if l.Kind == VariableLine && index == len(d.Lines) {
+			index = i
+		}
+	}
+
+	l := &DocumentLine{Kind: DataRecordLine, DataRecord: r}
+	d.insert(index, l)
+	d.setRecord(index, l, r, "")
+	d.renumber()
+	return nil
+}
+
+// RemoveRecord remove data record from Ads.txt document. Returns false if the data record is not part of the document
+func (d *Document) RemoveRecord(r *DataRecord) bool {
+	for i, l := range d.Lines {
+		if l.Kind == DataRecordLine && l.DataRecord == r {
+			d.remove(i)
+			d.renumber()
+			return true
+		}
+	}
+	return false
+}
+
+// ReplaceRecord replace data record of Ads.txt document with new data record. The comment of the replaced data
+// record is kept, unless the new data record declares its own comment. Returns false if the replaced data record
+// is not part of the document, and error if the new data record can not be written (see AddRecord)
+func (d *Document) ReplaceRecord(old *DataRecord, r *DataRecord) (bool, error) {
+	if err := validateDataRecordText(r); err != nil {
+		return false, fmt.Errorf(errEditDataRecord, r.AdverterDomain, err.Error())
+	}
+
+	for i, l := range d.Lines {
+		if l.Kind == DataRecordLine && l.DataRecord == old {
+			d.setRecord(i, l, r, old.Comment)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SetVariable set the value of the first variable of the specified type, or add new variable at the end of Ads.txt
+// document if no variable of this type is declared. Returns error if the variable type is not supported, or the value
+// can not be written as a single line parsed back as is (e.g. value containing "#")
+func (d *Document) SetVariable(t string, value string) error {
+	vt, ok := variableType(t)
+	if !ok {
+		return fmt.Errorf(errEditVariable, t, "variable type is not supported")
+	}
+	value = strings.TrimSpace(value)
+	if err := validateVariableText(&Variable{Type: vt, Value: value}); err != nil {
+		return fmt.Errorf(errEditVariable, t, err.Error())
+	}
+
+	t = vt
+	for _, l := range d.Lines {
+		if l.Kind == VariableLine && l.Variable.Type == t {
+			// keep the original variable name (e.g. uppercase "CONTACT") and comment
+			name := strings.TrimSpace(l.Raw[0:strings.Index(l.Raw, "=")])
+			l.Raw = name + "=" + value
+			if len(l.Variable.Comment) > 0 {
+				l.Raw += " " + commentDenote + " " + l.Variable.Comment
+			}
+			l.Variable.Value = value
+			l.Variable.Raw = l.Raw
+			return nil
+		}
+	}
+
+	v := &Variable{Type: t, Value: value}
+	l := &DocumentLine{Kind: VariableLine, Variable: v, Raw: v.String()}
+	d.insert(len(d.Lines), l)
+	v.Raw, v.Line = l.Raw, len(d.Lines)
+	return nil
+}
+
+// RemoveVariables remove all variables of the specified type from Ads.txt document, and return the number of
+// variables removed
+func (d *Document) RemoveVariables(t string) int {
+	t = strings.ToLower(t)
+	return d.removeLines(func(i int, l *DocumentLine) bool {
+		return l.Kind == VariableLine && l.Variable.Type == t
+	})
+}
+
+// setRecord set data record of Ads.txt document line at the specified position, and update the line text accordingly.
+// Data record fields are normalized the way they are parsed back from the line text
+func (d *Document) setRecord(index int, l *DocumentLine, r *DataRecord, comment string) {
+	if len(r.Comment) > 0 {
+		comment = r.Comment
+	}
+
+	normalizeDataRecordText(r)
+	l.DataRecord = r
+	l.Raw = r.String()
+	if len(comment) > 0 {
+		l.Raw += " " + commentDenote + " " + comment
+	}
+
+	r.Raw, r.Comment, r.Line = l.Raw, comment, index+1
+}
+
+// insert new line into Ads.txt document at the specified position. Line numbers of the following lines records are
+// not updated, callers renumber the document once done editing it
+func (d *Document) insert(index int, l *DocumentLine) {
+	l.EOL = d.eol()
+
+	// line added at the end of the file keeps the file ending as is (with or without end-of-line marker)
+	if index == len(d.Lines) && index > 0 {
+		last := d.Lines[index-1]
+		l.EOL, last.EOL = last.EOL, d.eol()
+	}
+
+	d.Lines = append(d.Lines, nil)
+	copy(d.Lines[index+1:], d.Lines[index:])
+	d.Lines[index] = l
+}
+
+// remove line from Ads.txt document. Line numbers of the following lines records are not updated, callers renumber
+// the document once done editing it
+func (d *Document) remove(index int) {
+	// removing the last line of the file keeps the file ending as is (with or without end-of-line marker)
+	if index == len(d.Lines)-1 && index > 0 {
+		d.Lines[index-1].EOL = d.Lines[index].EOL
+	}
+
+	d.Lines = append(d.Lines[:index], d.Lines[index+1:]...)
+}
+
+// removeLines remove all the lines for which remove returns true from Ads.txt document in a single pass, renumber the
+// document and return the number of lines removed
+func (d *Document) removeLines(remove func(i int, l *DocumentLine) bool) int {
+	if len(d.Lines) == 0 {
+		return 0
+	}
+
+	last := d.Lines[len(d.Lines)-1]
+	kept := []*DocumentLine{}
+	for i, l := range d.Lines {
+		if !remove(i, l) {
+			kept = append(kept, l)
+		}
+	}
+
+	removed := len(d.Lines) - len(kept)
+	if removed == 0 {
+		return 0
+	}
+
+	// removing the last line of the file keeps the file ending as is (with or without end-of-line marker)
+	if n := len(kept); n > 0 && kept[n-1] != last {
+		kept[n-1].EOL = last.EOL
+	}
+
+	d.Lines = kept
+	d.renumber()
+	return removed
+}
+
+// eol return the end-of-line marker used by Ads.txt document (based on its first line)
+func (d *Document) eol() string {
+	for _, l := range d.Lines {
+		if len(l.EOL) > 0 {
+			return l.EOL
+		}
+	}
+	return "\n"
+}
+
+// renumber update the line number of the document data records and variables after the document was edited
+func (d *Document) renumber() {
+	for i, l := range d.Lines {
+		if l.DataRecord != nil {
+			l.DataRecord.Line = i + 1
+		}
+		if l.Variable != nil {
+			l.Variable.Line = i + 1
+		}
+	}
+}
+
+// splitLinesEOL split Ads.txt file content into lines, each line including its end-of-line marker (CR, LF or CRLF)
+func splitLinesEOL(s string) []string {
+	lines := []string{}
+	for len(s) > 0 {
+		i := strings.IndexAny(s, "\r\n")
+		if i == -1 {
+			lines = append(lines, s)
+			break
+		}
+
+		end := i + 1
+		if s[i] == '\r' && end < len(s) && s[end] == '\n' {
+			end++
+		}
+		lines = append(lines, s[0:end])
+		s = s[end:]
+	}
+	return lines
+}
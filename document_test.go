@@ -0,0 +1,177 @@
+package adstxt
+
+import (
+	"bytes"
+	"testing"
+)
+
+// testDocument Ads.txt file mixing line endings, comments, blank lines and invalid lines, without final end-of-line
+const testDocument = "# ads.txt file for example.com\r\n" +
+	"\r\n" +
+	"google.com, pub-1234, DIRECT, f08c47fec0942fa0 # display\r\n" +
+	"  appnexus.com ,  5678 , RESELLER\n" +
+	"this line is not valid\n" +
+	"CONTACT=ads@example.com\n" +
+	"subdomain=divisionone.example.com"
+
+// TestParseDocumentRoundTrip test that unmodified Ads.txt document is serialized byte-identical
+func TestParseDocumentRoundTrip(t *testing.T) {
+	bodies := []string{
+		testDocument,
+		testDocument + "\n",
+		"",
+		"\n\n",
+		"google.com, pub-1234, DIRECT\r",
+		"google.com, pub-1234, DIRECT\r\rappnexus.com, 5678, RESELLER\n",
+	}
+
+	for _, body := range bodies {
+		d := ParseDocument([]byte(body))
+		if b := d.Bytes(); string(b) != body {
+			t.Errorf("Expected document to be serialized as [%q] but recieved [%q]", body, string(b))
+		}
+
+		var buf bytes.Buffer
+		n, err := d.WriteTo(&buf)
+		if err != nil || n != int64(len(body)) || buf.String() != body {
+			t.Errorf("Expected document to be written as [%q] but recieved [%q] ([%d] bytes, %v)", body, buf.String(), n, err)
+		}
+	}
+}
+
+// TestParseDocumentLines test classification of Ads.txt document lines
+func TestParseDocumentLines(t *testing.T) {
+	d := ParseDocument([]byte(testDocument))
+
+	kinds := []LineKind{CommentLine, BlankLine, DataRecordLine, DataRecordLine, UnknownLine, VariableLine, VariableLine}
+	eols := []string{"\r\n", "\r\n", "\r\n", "\n", "\n", "\n", ""}
+	if len(d.Lines) != len(kinds) {
+		t.Fatalf("Expected [%d] document lines but recieved [%d]", len(kinds), len(d.Lines))
+	}
+	for i, l := range d.Lines {
+		if l.Kind != kinds[i] {
+			t.Errorf("Expected line [%d] kind to be [%d] but recieved [%d]", i+1, kinds[i], l.Kind)
+		}
+		if l.EOL != eols[i] {
+			t.Errorf("Expected line [%d] EOL to be [%q] but recieved [%q]", i+1, eols[i], l.EOL)
+		}
+	}
+
+	if r := d.Lines[3].DataRecord; r.AdverterDomain != "appnexus.com" || r.PublisherAccountID != "5678" || r.Line != 4 {
+		t.Errorf("Expected line [4] to declare appnexus.com data record but recieved [%v]", r)
+	}
+	if len(d.DataRecords()) != 2 || len(d.Variables()) != 2 {
+		t.Errorf("Expected [2] data records and [2] variables but recieved [%d] and [%d]", len(d.DataRecords()), len(d.Variables()))
+	}
+}
+
+// TestDocumentEdit test editing Ads.txt document records and variables
+func TestDocumentEdit(t *testing.T) {
+	d := ParseDocument([]byte(testDocument))
+	records := d.DataRecords()
+
+	// replace google.com record: comment is kept
+	r := &DataRecord{AdverterDomain: "google.com", PublisherAccountID: "pub-9999", AccountType: "DIRECT", CertAuthorityID: "f08c47fec0942fa0"}
+	if ok, err := d.ReplaceRecord(records[0], r); !ok || err != nil {
+		t.Errorf("Expected google.com data record to be replaced (%v)", err)
+	}
+
+	// remove appnexus.com record, and add new one
+	if !d.RemoveRecord(records[1]) {
+		t.Errorf("Expected appnexus.com data record to be removed")
+	}
+	if d.RemoveRecord(records[1]) {
+		t.Errorf("Expected appnexus.com data record not to be removed twice")
+	}
+	if err := d.AddRecord(&DataRecord{AdverterDomain: "rubiconproject.com", PublisherAccountID: "1111", AccountType: "reseller ", Comment: "new"}); err != nil {
+		t.Error(err)
+	}
+
+	// update existing variable and add new variable
+	if err := d.SetVariable("contact", "adops@example.com"); err != nil {
+		t.Error(err)
+	}
+	if err := d.SetVariable("OWNERDOMAIN", "example.com"); err != nil {
+		t.Error(err)
+	}
+
+	expected := "# ads.txt file for example.com\r\n" +
+		"\r\n" +
+		"google.com, pub-9999, DIRECT, f08c47fec0942fa0 # display\r\n" +
+		"rubiconproject.com, 1111, RESELLER # new\r\n" +
+		"this line is not valid\n" +
+		"CONTACT=adops@example.com\n" +
+		"subdomain=divisionone.example.com\r\n" +
+		"ownerdomain=example.com"
+
+	if b := string(d.Bytes()); b != expected {
+		t.Errorf("Expected edited document to be [%q] but recieved [%q]", expected, b)
+	}
+
+	records = d.DataRecords()
+	if records[1].AdverterDomain != "rubiconproject.com" || records[1].Line != 4 {
+		t.Errorf("Expected rubiconproject.com data record on line [4] but recieved [%v] on line [%d]", records[1], records[1].Line)
+	}
+	if n := d.RemoveVariables("subdomain"); n != 1 {
+		t.Errorf("Expected [1] subdomain variable to be removed but recieved [%d]", n)
+	}
+	if v := d.Variables(); v[1].Type != varTypeOwnerDomain || v[1].Line != 7 {
+		t.Errorf("Expected ownerdomain variable on line [7] but recieved [%v] on line [%d]", v[1], v[1].Line)
+	}
+
+	// edited document is still valid Ads.txt file
+	rec, err := ParseBody(d.Bytes())
+	if err != nil || len(rec.DataRecords) != 2 || len(rec.Variables) != 2 {
+		t.Errorf("Expected edited document to be valid Ads.txt file but recieved [%v]", rec)
+	}
+}
+
+// TestDocumentAddRecordEmpty test adding data record to Ads.txt document without data records
+func TestDocumentAddRecordEmpty(t *testing.T) {
+	d := ParseDocument([]byte("# header\ncontact=ads@example.com\n"))
+	d.AddRecord(&DataRecord{AdverterDomain: "google.com", PublisherAccountID: "pub-1234", AccountType: "DIRECT"})
+
+	expected := "# header\ngoogle.com, pub-1234, DIRECT\ncontact=ads@example.com\n"
+	if b := string(d.Bytes()); b != expected {
+		t.Errorf("Expected document to be [%q] but recieved [%q]", expected, b)
+	}
+}
+
+// TestDocumentEditInvalid test edits which would not be parsed back as is are refused, leaving the document unchanged
+func TestDocumentEditInvalid(t *testing.T) {
+	d := ParseDocument([]byte(testDocument))
+	records := d.DataRecords()
+
+	invalid := []*DataRecord{
+		{AdverterDomain: "a,b", PublisherAccountID: "1", AccountType: "DIRECT"},
+		{AdverterDomain: "google.com", PublisherAccountID: "1 # 2", AccountType: "DIRECT"},
+		{AdverterDomain: "google.com", PublisherAccountID: "", AccountType: "DIRECT"},
+		{AdverterDomain: "google.com", PublisherAccountID: "1", AccountType: "PARTNER"},
+		{AdverterDomain: "contact=google.com", PublisherAccountID: "1", AccountType: "DIRECT"},
+		{AdverterDomain: "google.com", PublisherAccountID: "1", AccountType: "DIRECT", Comment: "two\nlines"},
+	}
+	for _, r := range invalid {
+		if err := d.AddRecord(r); err == nil {
+			t.Errorf("Expected error when adding data record [%s]", r)
+		}
+		if ok, err := d.ReplaceRecord(records[0], r); ok || err == nil {
+			t.Errorf("Expected error when replacing data record with [%s]", r)
+		}
+	}
+
+	variables := []struct{ t, value string }{
+		{"contact", "x # y\nfoo"},
+		{"contact", "x\nfoo"},
+		{"unknown", "example.com"},
+		{"contact=x", "example.com"},
+	}
+	for _, v := range variables {
+		if err := d.SetVariable(v.t, v.value); err == nil {
+			t.Errorf("Expected error when setting [%s] variable to [%q]", v.t, v.value)
+		}
+	}
+
+	if b := string(d.Bytes()); b != testDocument {
+		t.Errorf("Expected document not to be changed by invalid edits but recieved [%q]", b)
+	}
+}
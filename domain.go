@@ -0,0 +1,209 @@
+package adstxt
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
+)
+
+// normalizeDomain return domain name in its normalized form: IDNA ASCII form (punycode), lowercase and without
+// trailing dot. Domain which is not a valid IDNA domain name is only lowercased and trimmed
+func normalizeDomain(domain string) string {
+	d := strings.TrimSuffix(strings.TrimSpace(domain), ".")
+
+	// IDNA ASCII form of ASCII domain name is the domain name itself, lowercased (ToLower does not allocate
+	// if the domain is already lowercase)
+	if isASCII(d) {
+		return strings.ToLower(d)
+	}
+
+	if ascii, err := idna.Lookup.ToASCII(d); err == nil {
+		d = ascii
+	}
+	return strings.ToLower(d)
+}
+
+// isASCII check if all the string characters are ASCII characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeHost return URL host (with optional port) in its normalized form
+func normalizeHost(host string) string {
+	if h, port, ok := splitHostPort(host); ok {
+		return normalizeDomain(h) + ":" + port
+	}
+	return normalizeDomain(host)
+}
+
+// splitHostPort split URL host into host name and port, if the host declares a port
+func splitHostPort(host string) (string, string, bool) {
+	i := strings.LastIndex(host, ":")
+	if i == -1 || strings.Contains(host[i:], "]") {
+		return host, "", false
+	}
+	return host[0:i], host[i+1:], true
+}
+
+// normalizeURL return URL with its host in normalized form. URL which could not be parsed is returned as is
+func normalizeURL(rawurl string) string {
+	u, err := url.Parse(rawurl)
+	if err != nil || len(u.Host) == 0 {
+		return rawurl
+	}
+
+	u.Host = normalizeHost(u.Host)
+	return u.String()
+}
+
+// Root domain resolution error
+const (
+	errRootDomain            = "Failed to resolve root domain of [%s]: %s"
+	errRootDomainEmptyHost   = "host is empty"
+	errRootDomainInvalidHost = "host is not a valid host name"
+	errRootDomainSuffix      = "host is a public suffix"
+)
+
+// RootDomainError is returned when there is no valid root domain for the specified URL host: host is empty, is not
+// a valid host name, or is itself a public suffix (e.g. "co.uk" or, unless DomainResolver.ICANNOnly is set,
+// "s3.amazonaws.com")
+type RootDomainError struct {
+	Host   string // Host of the URL which root domain was resolved
+	Suffix string // Suffix public suffix of the host, empty if the host is not a public suffix
+	Reason string // Reason the root domain could not be resolved
+}
+
+// Error is the error interface implementation for the RootDomainError type
+func (e *RootDomainError) Error() string {
+	return fmt.Sprintf(errRootDomain, e.Host, e.Reason)
+}
+
+// DomainResolver resolve the root domain of URL host, defined as the “public suffix” plus one string in the name.
+// Public suffixes are taken from the Public Suffix List, including its private domains section (e.g. "blogspot.com"
+// or "s3.amazonaws.com") unless ICANNOnly is set. The list compiled into golang.org/x/net/publicsuffix is used,
+// unless a list loaded at runtime is set (see LoadSuffixList)
+type DomainResolver struct {
+	ICANNOnly bool        // ICANNOnly use only the ICANN section of the Public Suffix List, ignoring private suffixes
+	Suffixes  *SuffixList // Suffixes Public Suffix List loaded at runtime (compiled-in list if not set)
+}
+
+// defaultResolver private suffix aware domain resolver, used by NewRequest and by the crawler if not set otherwise
+var defaultResolver = &DomainResolver{}
+
+// RootDomain return the root domain of the specified URL host. IP address hosts (v4 or v6) are their own root domain.
+// URL scheme, user info, port and path are ignored, and host is normalized (IDNA ASCII form, lowercase, no trailing
+// dot). RootDomainError is returned if host is empty, is not a valid host name or is itself a public suffix
+func (r *DomainResolver) RootDomain(rawurl string) (string, error) {
+	host := normalizeDomain(urlHost(rawurl))
+	if len(host) == 0 {
+		return "", &RootDomainError{Host: rawurl, Reason: errRootDomainEmptyHost}
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String(), nil
+	}
+	if !isValidHost(host) {
+		return "", &RootDomainError{Host: host, Reason: errRootDomainInvalidHost}
+	}
+
+	suffix := r.publicSuffix(host)
+	if host == suffix {
+		return "", &RootDomainError{Host: host, Suffix: suffix, Reason: errRootDomainSuffix}
+	}
+
+	// public suffix plus one label
+	root := strings.TrimSuffix(host, "."+suffix)
+	if i := strings.LastIndex(root, "."); i != -1 {
+		root = root[i+1:]
+	}
+	return root + "." + suffix, nil
+}
+
+// publicSuffix return the public suffix of the host. When resolving ICANN suffixes only, private suffixes are
+// skipped by looking up the suffix of their parent domain, until an ICANN suffix (or unlisted top level domain) is found
+func (r *DomainResolver) publicSuffix(host string) string {
+	suffix, icann := r.lookup(host)
+	for r.ICANNOnly && !icann {
+		parent := parentDomain(suffix)
+		if len(parent) == 0 {
+			break
+		}
+		suffix, icann = r.lookup(parent)
+	}
+	return suffix
+}
+
+// lookup return the public suffix of the domain, and whether it is an ICANN suffix, using the runtime loaded list if
+// set and the compiled-in list otherwise
+func (r *DomainResolver) lookup(domain string) (string, bool) {
+	if r.Suffixes != nil {
+		return r.Suffixes.PublicSuffix(domain)
+	}
+	return publicsuffix.PublicSuffix(domain)
+}
+
+// isValidHost check if normalized host name is made of non empty labels of ASCII letters, digits, hyphens and
+// underscores
+func isValidHost(host string) bool {
+	for _, label := range strings.Split(host, ".") {
+		if len(label) == 0 || strings.TrimFunc(label, func(r rune) bool {
+			return ('a' <= r && r <= 'z') || ('0' <= r && r <= '9') || r == '-' || r == '_'
+		}) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// rootDomain return the root domain of the specified URL using the default domain resolver
+func rootDomain(rawurl string) (string, error) {
+	return defaultResolver.RootDomain(rawurl)
+}
+
+// hasScheme check if URL starts with a scheme (e.g. "https://"), rather than including "://" in its path or query
+func hasScheme(rawurl string) bool {
+	i := strings.Index(rawurl, "://")
+	return i > 0 && !strings.ContainsAny(rawurl[0:i], "/?#")
+}
+
+// urlHost extract host name from specified URL: remove scheme (http/s), user info, path, query and port. URL does
+// not have to be valid (e.g. scheme may be missing). IPv6 hosts are returned without brackets
+func urlHost(rawurl string) string {
+	host := strings.TrimSpace(rawurl)
+
+	// remove scheme
+	if hasScheme(host) {
+		host = host[strings.Index(host, "://")+3:]
+	}
+
+	// remove path, query and fragment
+	if i := strings.IndexAny(host, "/?#"); i != -1 {
+		host = host[0:i]
+	}
+
+	// remove user info
+	if i := strings.LastIndex(host, "@"); i != -1 {
+		host = host[i+1:]
+	}
+
+	// remove port: IPv6 hosts are enclosed in brackets when declaring a port, and have more than a single colon otherwise
+	if strings.HasPrefix(host, "[") {
+		if i := strings.Index(host, "]"); i != -1 {
+			return host[1:i]
+		}
+	}
+	if strings.Count(host, ":") == 1 {
+		host = host[0:strings.Index(host, ":")]
+	}
+	return host
+}
@@ -0,0 +1,137 @@
+package adstxt
+
+import (
+	"testing"
+)
+
+// TestNormalizeDomain test normalizing domain names: IDNA ASCII form, lowercase and no trailing dot
+func TestNormalizeDomain(t *testing.T) {
+	domains := map[string]string{
+		"example.com":           "example.com",
+		"Example.COM":           "example.com",
+		"example.com.":          "example.com",
+		" example.com ":         "example.com",
+		"bücher.de":             "xn--bcher-kva.de",
+		"BÜCHER.de.":            "xn--bcher-kva.de",
+		"xn--bcher-kva.de":      "xn--bcher-kva.de",
+		"google.com/adsense":    "google.com/adsense",
+		"Ad_System.example.com": "ad_system.example.com",
+	}
+
+	for k, v := range domains {
+		if res := normalizeDomain(k); res != v {
+			t.Errorf("Expected [%s] normalized domain to be [%s] but recieved [%s]", k, v, res)
+		}
+	}
+}
+
+// TestNormalizeURL test normalizing URL host name
+func TestNormalizeURL(t *testing.T) {
+	urls := map[string]string{
+		"http://WWW.Example.com/ads.txt":       "http://www.example.com/ads.txt",
+		"https://bücher.de./ads.txt":           "https://xn--bcher-kva.de/ads.txt",
+		"http://Example.com:8080/Path/ads.txt": "http://example.com:8080/Path/ads.txt",
+		"/ads.txt":                             "/ads.txt",
+	}
+
+	for k, v := range urls {
+		if res := normalizeURL(k); res != v {
+			t.Errorf("Expected [%s] normalized URL to be [%s] but recieved [%s]", k, v, res)
+		}
+	}
+}
+
+// TestNormalizedDomainRegistry test looking up ad system using non normalized domain
+func TestNormalizedDomainRegistry(t *testing.T) {
+	r, _ := NewRegistry([]*AdSystem{{ID: 1, Name: "Bücher", CanonicalDomains: []string{"bücher.de"}}},
+		[]*AdSystemDomain{{Domain: "ads.bücher.de", ID: 1}})
+
+	for _, d := range []string{"xn--bcher-kva.de", "BÜCHER.de.", "ads.xn--bcher-kva.de", "ads.bücher.de"} {
+		if _, ok := r.Lookup(d); !ok {
+			t.Errorf("Expected [%s] to be a known ad system domain", d)
+		}
+	}
+	if canonical, _ := r.Canonicalize("ads.bücher.de"); canonical != "xn--bcher-kva.de" {
+		t.Errorf("Expected [ads.bücher.de] canonical domain to be [xn--bcher-kva.de] but recieved [%s]", canonical)
+	}
+}
+
+// TestRootDomainICANNOnly test resolving root domain using only the ICANN section of the public suffix list
+func TestRootDomainICANNOnly(t *testing.T) {
+	domains := map[string]string{
+		"http://www.example.com/":     "example.com",
+		"www.example.co.uk":           "example.co.uk",
+		"bucket.s3.amazonaws.com":     "amazonaws.com",
+		"https://testme.blogspot.com": "blogspot.com",
+		"s3.amazonaws.com":            "amazonaws.com",
+		"127.0.0.1":                   "127.0.0.1",
+	}
+
+	r := &DomainResolver{ICANNOnly: true}
+	for k, v := range domains {
+		res, err := r.RootDomain(k)
+		if err != nil {
+			t.Error(err)
+		}
+		if res != v {
+			t.Errorf("Expected [%s] root domain to be [%s] and not [%s]", k, v, res)
+		}
+	}
+}
+
+// TestRootDomainError test resolving root domain of hosts which have no valid root domain
+func TestRootDomainError(t *testing.T) {
+	hosts := map[string]string{
+		"":                         "",
+		"http:///ads.txt":          "",
+		"com":                      "com",
+		"https://co.uk/ads.txt":    "co.uk",
+		"s3.amazonaws.com":         "s3.amazonaws.com",
+		"http://blogspot.com:8080": "blogspot.com",
+		"not a domain.com":         "",
+		"http://.example.com/":     "",
+		"www..example.com":         "",
+	}
+
+	for k, v := range hosts {
+		res, err := rootDomain(k)
+		rootErr, ok := err.(*RootDomainError)
+		if !ok {
+			t.Errorf("Expected [%s] to fail with RootDomainError but recieved [%s] [%v]", k, res, err)
+			continue
+		}
+		if rootErr.Suffix != v {
+			t.Errorf("Expected [%s] public suffix to be [%s] but recieved [%s]", k, v, rootErr.Suffix)
+		}
+	}
+
+	if _, err := NewRequest("co.uk"); err == nil {
+		t.Error("Expected request for public suffix [co.uk] to fail")
+	}
+}
+
+// FuzzRootDomain fuzz resolving root domain: root domain is normalized and is its own root domain, and failures
+// are reported as RootDomainError
+func FuzzRootDomain(f *testing.F) {
+	for _, seed := range []string{"example.com", "http://sub.domain.test.com", "https://testme.tumblr.com/", "co.uk",
+		"bucket.s3.amazonaws.com", "http://[::1]:8080/ads.txt", "2001:db8::1", "BÜCHER.de.", "user@www.port.com:8080", ""} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, rawurl string) {
+		d, err := rootDomain(rawurl)
+		if err != nil {
+			if _, ok := err.(*RootDomainError); !ok {
+				t.Errorf("Expected root domain of [%q] to fail with RootDomainError but recieved [%v]", rawurl, err)
+			}
+			return
+		}
+
+		if len(d) == 0 || normalizeDomain(d) != d {
+			t.Errorf("Expected root domain [%s] of [%q] to be normalized", d, rawurl)
+		}
+		if again, err := rootDomain(d); err != nil || again != d {
+			t.Errorf("Expected root domain [%s] of [%q] to be its own root domain but recieved [%s] [%v]", d, rawurl, again, err)
+		}
+	})
+}
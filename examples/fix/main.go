@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/ehulsbosch/go-adstxt-crawler"
+)
+
+func main() {
+	write := flag.Bool("w", false, "write fixed Ads.txt file back to its source file instead of stdout")
+	flag.Parse()
+
+	// fix local file
+	path := flag.Arg(0)
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	res := adstxt.Fix(body)
+
+	for _, c := range res.Changes {
+		log.Printf("line [%d] %s: %s", c.Line, c.Fix, c.Description)
+	}
+
+	if *write {
+		if err := ioutil.WriteFile(path, res.Body, 0644); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	os.Stdout.Write(res.Body)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/ehulsbosch/go-adstxt-crawler"
+)
+
+func main() {
+	page := flag.String("html", "", "saved copy of the IAB normalization mappings wiki page ("+adstxt.NormalizationMappingsURL+")")
+	adSystems := flag.String("adsystems", "", "ad systems table exported as CSV (ID, NAME, CANONICAL_DOMAIN)")
+	domains := flag.String("domains", "", "domains table exported as CSV (DOMAIN, ID)")
+	current := flag.String("current", "", "current registry JSON file (default: registry shipped with the package)")
+	out := flag.String("o", "", "write imported registry as JSON to this file")
+	flag.Parse()
+
+	// import registry from the saved wiki page or from its exported CSV tables
+	var next *adstxt.Registry
+	var err error
+	switch {
+	case len(*page) > 0:
+		f, openErr := os.Open(*page)
+		if openErr != nil {
+			log.Fatal(openErr)
+		}
+		next, err = adstxt.ImportRegistryHTML(f)
+		f.Close()
+	case len(*adSystems) > 0 && len(*domains) > 0:
+		next, err = adstxt.LoadRegistryCSVFiles(*adSystems, *domains)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cur := adstxt.DefaultRegistry()
+	if len(*current) > 0 {
+		if cur, err = adstxt.LoadRegistryFile(*current); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// keep the metadata of the current registry which is not part of the normalization mappings (certification
+	// authority IDs and account ID patterns)
+	if next, err = adstxt.MergeRegistryMetadata(cur, next); err != nil {
+		log.Fatal(err)
+	}
+
+	// report the differences compared to the current registry
+	diff := adstxt.DiffRegistry(cur, next)
+	if diff.Empty() {
+		fmt.Println("registry is up to date")
+	} else {
+		fmt.Println(diff)
+	}
+
+	if len(*out) > 0 {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		if err := next.WriteJSON(f); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
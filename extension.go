@@ -0,0 +1,127 @@
+package adstxt
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Ads.txt extension
+const (
+	// Extension data is separated from the data record fields by the character ";"
+	extensionDenote = ";"
+	// Separator between <KEY>=<VALUE> pairs in extension data parsed by KeyValueExtensionParser
+	extensionPairDenote = ";"
+)
+
+// The ExtensionParser interface is used to parse ad system specific extension data declared
+// after the ";" separator of an Ads.txt data record.
+type ExtensionParser interface {
+	Parse(ext string) (map[string]string, error)
+}
+
+// An ExtensionParserFunc is a function signature that implements the ExtensionParser interface.
+// A function with this signature can thus be used as an ExtensionParser.
+type ExtensionParserFunc func(string) (map[string]string, error)
+
+// Parse is the ExtensionParser interface implementation for the ExtensionParserFunc type.
+func (f ExtensionParserFunc) Parse(ext string) (map[string]string, error) {
+	return f(ext)
+}
+
+// KeyValueExtensionParser parse extension data declared as a list of <KEY>=<VALUE> pairs
+// separated by the character ";"
+var KeyValueExtensionParser = ExtensionParserFunc(parseKeyValueExtension)
+
+var (
+	extensionParsers = make(map[string]ExtensionParser)
+	extensionLock    = sync.RWMutex{}
+)
+
+// RegisterExtensionParser register extension parser for data records of the specified ad system domain
+// (case insensitive). The parser applies to all known domains of the ad system, as found in the registry used to parse
+// the Ads.txt file. Registering nil parser removes previously registered parser for that domain.
+func RegisterExtensionParser(domain string, p ExtensionParser) {
+	extensionLock.Lock()
+	defer extensionLock.Unlock()
+
+	domain = normalizeDomain(domain)
+	if p == nil {
+		delete(extensionParsers, domain)
+		return
+	}
+	extensionParsers[domain] = p
+}
+
+// extensionParserSet extension parsers used by a parser, keyed by ad system (see Registry.key)
+type extensionParserSet struct {
+	registry *Registry
+	parsers  map[string]ExtensionParser
+}
+
+// newExtensionParserSet return the registered extension parsers along with the specified parsers (which override the
+// registered parsers of the same ad system), keyed by ad system using the specified registry
+func newExtensionParserSet(reg *Registry, parsers map[string]ExtensionParser) *extensionParserSet {
+	extensionLock.RLock()
+	defer extensionLock.RUnlock()
+
+	s := &extensionParserSet{registry: reg, parsers: make(map[string]ExtensionParser, len(extensionParsers)+len(parsers))}
+	for domain, p := range extensionParsers {
+		s.parsers[reg.key(domain)] = p
+	}
+	for domain, p := range parsers {
+		if p != nil {
+			s.parsers[reg.key(domain)] = p
+		}
+	}
+	return s
+}
+
+// lookup return extension parser of the ad system of the specified domain
+func (s *extensionParserSet) lookup(domain string) (ExtensionParser, bool) {
+	if s == nil || len(s.parsers) == 0 {
+		return nil, false
+	}
+	p, ok := s.parsers[s.registry.key(domain)]
+	return p, ok
+}
+
+// splitExtension split Ads.txt data record line into the record fields and the extension data
+func splitExtension(line string) (string, field) {
+	index := strings.Index(line, extensionDenote)
+	if index == -1 {
+		return line, field{}
+	}
+
+	raw := line[index+1:]
+	value := strings.TrimSpace(raw)
+	column := index + len(raw) - len(strings.TrimLeftFunc(raw, unicode.IsSpace)) + 2
+	return line[0:index], field{value: value, column: column, endColumn: column + len(value)}
+}
+
+// parseKeyValueExtension parse extension data declared as a list of <KEY>=<VALUE> pairs. All pairs are parsed: the
+// valid pairs are returned along with an error listing the invalid pairs (if any)
+func parseKeyValueExtension(ext string) (map[string]string, error) {
+	fields := map[string]string{}
+	invalid := []string{}
+	for _, pair := range strings.Split(ext, extensionPairDenote) {
+		pair = strings.TrimSpace(pair)
+		if len(pair) == 0 {
+			continue
+		}
+
+		kv := strings.SplitN(pair, "=", 2)
+		key := strings.TrimSpace(kv[0])
+		if len(kv) != 2 || len(key) == 0 {
+			invalid = append(invalid, pair)
+			continue
+		}
+		fields[key] = strings.TrimSpace(kv[1])
+	}
+
+	if len(invalid) > 0 {
+		return fields, fmt.Errorf("[%s] must be declared as <KEY>=<VALUE> pair", strings.Join(invalid, "], ["))
+	}
+	return fields, nil
+}
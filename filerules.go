@@ -0,0 +1,263 @@
+package adstxt
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Built-in file level validation rules names, used to disable a rule using ParseOptions
+const (
+	// RuleDuplicateRecords validate that each data record is declared only once
+	RuleDuplicateRecords = "duplicate-records"
+	// RuleConflictingAccountType validate that ad system account is not declared as both DIRECT and RESELLER
+	RuleConflictingAccountType = "conflicting-account-type"
+	// RuleConsistentCertAuthorityID validate that all data records of an ad system declare the same certification authority ID
+	RuleConsistentCertAuthorityID = "consistent-cert-authority-id"
+	// RuleDuplicateVariables validate that variables are not declared more than once
+	RuleDuplicateVariables = "duplicate-variables"
+	// RuleDataRecordsRequired validate that Ads.txt file has at least one data record
+	RuleDataRecordsRequired = "data-records-required"
+)
+
+// The FileChecker interface is used to validate Ads.txt file as a whole. FileChecker observe each Ads.txt line
+// accepted by the parser, and returns the warnings found once all lines were parsed
+type FileChecker interface {
+	Observe(l *Line)    // Observe parsed Ads.txt line
+	Finish() []*Warning // Finish return the warnings found in Ads.txt file
+}
+
+// The FileRule interface is used to create a FileChecker for each parsed Ads.txt file
+type FileRule interface {
+	Name() string            // Name unique name of the rule, used to disable the rule
+	NewChecker() FileChecker // NewChecker create new checker to validate single Ads.txt file
+}
+
+// fileRule is a FileRule implementation wrapping a checker constructor
+type fileRule struct {
+	name       string
+	newChecker func() FileChecker
+}
+
+// Name is the FileRule interface implementation for the fileRule type
+func (r *fileRule) Name() string {
+	return r.name
+}
+
+// NewChecker is the FileRule interface implementation for the fileRule type
+func (r *fileRule) NewChecker() FileChecker {
+	return r.newChecker()
+}
+
+// NewFileRule create new named file level validation rule from the specified checker constructor
+func NewFileRule(name string, newChecker func() FileChecker) FileRule {
+	return &fileRule{name: name, newChecker: newChecker}
+}
+
+// builtinFileRules file level validation rules run on every parsed Ads.txt file, unless disabled
+var builtinFileRules = []FileRule{
+	NewFileRule(RuleDuplicateRecords, func() FileChecker { return newLineGroups(duplicateRecordKey, checkDuplicateRecords) }),
+	NewFileRule(RuleConflictingAccountType, func() FileChecker { return newLineGroups(accountKey, checkConflictingAccountType) }),
+	NewFileRule(RuleConsistentCertAuthorityID, func() FileChecker { return newLineGroups(certAuthorityKey, checkConsistentCertAuthorityID) }),
+	NewFileRule(RuleDuplicateVariables, func() FileChecker { return newLineGroups(variableKey, checkDuplicateVariables) }),
+}
+
+// dataRecordsRequiredRule file level validation rule reporting Ads.txt file without data records, run only if requested
+// by the parse options (ParseOptions.RequireDataRecords)
+var dataRecordsRequiredRule = NewFileRule(RuleDataRecordsRequired, func() FileChecker { return &dataRecordsRequired{} })
+
+// lineGroups is a FileChecker grouping Ads.txt lines by key, and checking each group of lines once all lines were parsed.
+// Most groups hold a single line, so only the first line of each group is kept until a second line is observed
+type lineGroups struct {
+	key   func(l *Line) string           // key of the group the line belongs to, empty key to ignore the line
+	check func(group []*Line) []*Warning // check validate single group of lines
+	index map[string]int                 // index of each group, by key
+	first []*Line                        // first line of each group, by order of appearance
+	more  map[int][]*Line                // lines following the first line, by group index
+}
+
+// newLineGroups create new FileChecker grouping Ads.txt lines by the specified key function
+func newLineGroups(key func(l *Line) string, check func(group []*Line) []*Warning) *lineGroups {
+	return &lineGroups{key: key, check: check, index: map[string]int{}, more: map[int][]*Line{}}
+}
+
+// Observe is the FileChecker interface implementation for the lineGroups type
+func (g *lineGroups) Observe(l *Line) {
+	k := g.key(l)
+	if len(k) == 0 {
+		return
+	}
+
+	if i, ok := g.index[k]; ok {
+		g.more[i] = append(g.more[i], l)
+		return
+	}
+	g.index[k] = len(g.first)
+	g.first = append(g.first, l)
+}
+
+// Finish is the FileChecker interface implementation for the lineGroups type
+func (g *lineGroups) Finish() []*Warning {
+	warnings := []*Warning{}
+	for i, l := range g.first {
+		if more, ok := g.more[i]; ok {
+			warnings = append(warnings, g.check(append([]*Line{l}, more...))...)
+		}
+	}
+	return warnings
+}
+
+// adSystemKey return key identifying the ad system of the line data record (see Registry.key). Key is computed once
+// and shared by all file level rules
+func (l *Line) adSystemKey() string {
+	if len(l.key) == 0 {
+		l.key = l.Registry.key(l.DataRecord.AdverterDomain)
+	}
+	return l.key
+}
+
+// duplicateRecordKey group data records with the same fields
+func duplicateRecordKey(l *Line) string {
+	if l.DataRecord == nil {
+		return ""
+	}
+	r := l.DataRecord
+	return l.adSystemKey() + "," + r.PublisherAccountID + "," + r.AccountType + "," + strings.ToLower(r.CertAuthorityID)
+}
+
+// accountKey group data records of the same ad system account
+func accountKey(l *Line) string {
+	if l.DataRecord == nil {
+		return ""
+	}
+	return l.adSystemKey() + "," + l.DataRecord.PublisherAccountID
+}
+
+// certAuthorityKey group data records of the same ad system, which declare certification authority ID
+func certAuthorityKey(l *Line) string {
+	if l.DataRecord == nil || len(l.DataRecord.CertAuthorityID) == 0 {
+		return ""
+	}
+	return l.adSystemKey()
+}
+
+// variableKey group variables of the same type. Variables which may be declared multiple times are grouped
+// by their value as well
+func variableKey(l *Line) string {
+	if l.Variable == nil {
+		return ""
+	}
+	if l.Variable.Type == varTypeOwnerDomain {
+		return l.Variable.Type
+	}
+	return l.Variable.Type + "=" + strings.TrimSpace(l.Variable.Value)
+}
+
+// checkDuplicateRecords report data record declared more than once
+func checkDuplicateRecords(group []*Line) []*Warning {
+	w := group[1].Warn(CodeDuplicateRecord, WarningSevirity, 0,
+		fmt.Sprintf("Data record is declared [%d] times, on lines %s", len(group), lineIndexes(group)), nil)
+	w.Indexes = indexes(group)
+	return []*Warning{w}
+}
+
+// checkConflictingAccountType report ad system account declared as both DIRECT and RESELLER
+func checkConflictingAccountType(group []*Line) []*Warning {
+	for _, l := range group[1:] {
+		if l.DataRecord.AccountType != group[0].DataRecord.AccountType {
+			r := l.DataRecord
+			w := l.Warn(CodeConflictingAccountType, WarningSevirity, 3,
+				fmt.Sprintf("Account [%s] of [%s] is declared as both [%s] and [%s], on lines %s",
+					r.PublisherAccountID, r.AdverterDomain, accountTypeDirect, accountTypeReseller, lineIndexes(group)),
+				map[string]string{"domain": r.AdverterDomain, "account": r.PublisherAccountID})
+			w.Indexes = indexes(group)
+			return []*Warning{w}
+		}
+	}
+	return nil
+}
+
+// checkConsistentCertAuthorityID report ad system data records declaring different certification authority IDs
+func checkConsistentCertAuthorityID(group []*Line) []*Warning {
+	ids := []string{group[0].DataRecord.CertAuthorityID}
+	var first *Line
+	for _, l := range group[1:] {
+		id := l.DataRecord.CertAuthorityID
+		if !containsFold(ids, id) {
+			ids = append(ids, id)
+			if first == nil {
+				first = l
+			}
+		}
+	}
+
+	if first == nil {
+		return nil
+	}
+
+	w := first.Warn(CodeInconsistentCertAuthorityID, WarningSevirity, 4,
+		fmt.Sprintf("Data records of [%s] declare different Certification Authority IDs [%s], on lines %s",
+			first.DataRecord.AdverterDomain, strings.Join(ids, ", "), lineIndexes(group)),
+		map[string]string{"domain": first.DataRecord.AdverterDomain, "values": strings.Join(ids, ",")})
+	w.Indexes = indexes(group)
+	return []*Warning{w}
+}
+
+// checkDuplicateVariables report variable declared more than once
+func checkDuplicateVariables(group []*Line) []*Warning {
+	t := group[0].Variable.Type
+	w := group[1].Warn(CodeDuplicateVariable, WarningSevirity, 0,
+		fmt.Sprintf("Variable [%s] is declared [%d] times, on lines %s", t, len(group), lineIndexes(group)), map[string]string{"type": t})
+	w.Indexes = indexes(group)
+	return []*Warning{w}
+}
+
+// dataRecordsRequired is a FileChecker reporting Ads.txt file without any data record
+type dataRecordsRequired struct {
+	found bool
+}
+
+// Observe is the FileChecker interface implementation for the dataRecordsRequired type
+func (c *dataRecordsRequired) Observe(l *Line) {
+	if l.DataRecord != nil {
+		c.found = true
+	}
+}
+
+// Finish is the FileChecker interface implementation for the dataRecordsRequired type
+func (c *dataRecordsRequired) Finish() []*Warning {
+	if c.found {
+		return nil
+	}
+	return []*Warning{newWarning(CodeNoDataRecords, WarningSevirity, field{}, "Ads.txt file has no valid data records", nil)}
+}
+
+// indexes return the indexes of the specified lines
+func indexes(lines []*Line) []int {
+	indexes := make([]int, len(lines))
+	for i, l := range lines {
+		indexes[i] = l.Index
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// lineIndexes return the indexes of the specified lines, formatted as text
+func lineIndexes(lines []*Line) string {
+	str := []string{}
+	for _, index := range indexes(lines) {
+		str = append(str, strconv.Itoa(index))
+	}
+	return "[" + strings.Join(str, ", ") + "]"
+}
+
+// containsFold check if the list contains the specified value (case insensitive)
+func containsFold(list []string, value string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
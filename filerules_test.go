@@ -0,0 +1,190 @@
+package adstxt
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fileWarnings return the warnings with the specified code
+func fileWarnings(res *Records, code string) []*Warning {
+	warnings := []*Warning{}
+	for _, w := range res.Warnings {
+		if w.Code == code {
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+// TestDuplicateRecords test validating Ads.txt file with duplicate data records
+func TestDuplicateRecords(t *testing.T) {
+	body := []string{
+		"greenadexchange.com, XF7342, DIRECT, 5jyxf8k54",
+		"greenadexchange.com, XF7342, RESELLER",
+		"GreenAdExchange.com,XF7342,DIRECT,5JYXF8K54 # same record",
+		"greenadexchange.com, XF7342, DIRECT, 5jyxf8k54",
+		"greenadexchange.com, XF7343, DIRECT, 5jyxf8k54",
+	}
+	res, _ := ParseBodyWithOptions([]byte(strings.Join(body, "\n")), &ParseOptions{Registry: testRegistry})
+
+	warnings := fileWarnings(res, CodeDuplicateRecord)
+	if len(warnings) != 1 {
+		t.Fatalf("Expected single [%s] warning but recieved [%d]", CodeDuplicateRecord, len(warnings))
+	}
+	if warnings[0].Index != 3 || !reflect.DeepEqual(warnings[0].Indexes, []int{1, 3, 4}) {
+		t.Errorf("Expected [%s] warning on line #3 involving lines [1 3 4] but recieved line #%d involving lines %v",
+			CodeDuplicateRecord, warnings[0].Index, warnings[0].Indexes)
+	}
+	if warnings[0].Text != body[2] {
+		t.Errorf("Expected [%s] warning text to be [%s] but recieved [%s]", CodeDuplicateRecord, body[2], warnings[0].Text)
+	}
+
+	// duplicate records are still valid records
+	if len(res.DataRecords) != len(body) {
+		t.Errorf("Expected [%d] DataRecords but recieved [%d]", len(body), len(res.DataRecords))
+	}
+}
+
+// TestConflictingAccountType test validating Ads.txt file with account declared as both DIRECT and RESELLER
+func TestConflictingAccountType(t *testing.T) {
+	body := []string{
+		"greenadexchange.com, XF7342, DIRECT",
+		"adtech.com, 100, RESELLER",
+		"greenadexchange.com, XF7343, DIRECT",
+		"aolcloud.net, 100, DIRECT",
+		"greenadexchange.com, XF7342, RESELLER",
+	}
+	res, _ := ParseBodyWithOptions([]byte(strings.Join(body, "\n")), &ParseOptions{Registry: testRegistry})
+
+	warnings := fileWarnings(res, CodeConflictingAccountType)
+	if len(warnings) != 2 {
+		t.Fatalf("Expected 2 [%s] warnings but recieved [%d]", CodeConflictingAccountType, len(warnings))
+	}
+
+	// records of ad system different domains are the same ad system account
+	expected := map[int][]int{5: []int{1, 5}, 4: []int{2, 4}}
+	for _, w := range warnings {
+		if !reflect.DeepEqual(expected[w.Index], w.Indexes) {
+			t.Errorf("Expected [%s] warning on line #%d involving lines %v but recieved %v", w.Code, w.Index, expected[w.Index], w.Indexes)
+		}
+		if w.Field != 3 {
+			t.Errorf("Expected [%s] warning to concern field #3 but recieved field #%d", w.Code, w.Field)
+		}
+	}
+}
+
+// TestConsistentCertAuthorityID test validating Ads.txt file with inconsistent certification authority IDs
+func TestConsistentCertAuthorityID(t *testing.T) {
+	body := []string{
+		"greenadexchange.com, XF7342, DIRECT, 5jyxf8k54",
+		"greenadexchange.com, XF7343, DIRECT",
+		"greenadexchange.com, XF7344, RESELLER, 5JYXF8K54",
+		"greenadexchange.com, XF7345, RESELLER, 7jyxf8k55",
+		"adtech.com, 100, RESELLER, 5jyxf8k54",
+	}
+	res, _ := ParseBodyWithOptions([]byte(strings.Join(body, "\n")), &ParseOptions{Registry: testRegistry})
+
+	warnings := fileWarnings(res, CodeInconsistentCertAuthorityID)
+	if len(warnings) != 1 {
+		t.Fatalf("Expected single [%s] warning but recieved [%d]", CodeInconsistentCertAuthorityID, len(warnings))
+	}
+	if warnings[0].Index != 4 || !reflect.DeepEqual(warnings[0].Indexes, []int{1, 3, 4}) {
+		t.Errorf("Expected [%s] warning on line #4 involving lines [1 3 4] but recieved line #%d involving lines %v",
+			CodeInconsistentCertAuthorityID, warnings[0].Index, warnings[0].Indexes)
+	}
+	if warnings[0].Params["values"] != "5jyxf8k54,7jyxf8k55" {
+		t.Errorf("Expected [%s] warning values to be [5jyxf8k54,7jyxf8k55] but recieved [%s]", CodeInconsistentCertAuthorityID, warnings[0].Params["values"])
+	}
+}
+
+// TestDuplicateVariables test validating Ads.txt file with duplicate variables
+func TestDuplicateVariables(t *testing.T) {
+	body := []string{
+		"greenadexchange.com, XF7342, DIRECT",
+		"contact=adops@example.com",
+		"contact=https://example.com/contact",
+		"contact=adops@example.com",
+		"OWNERDOMAIN=example.com",
+		"ownerdomain=example.org",
+	}
+	res, _ := ParseBodyWithOptions([]byte(strings.Join(body, "\n")), &ParseOptions{Registry: testRegistry})
+
+	warnings := fileWarnings(res, CodeDuplicateVariable)
+	if len(warnings) != 2 {
+		t.Fatalf("Expected 2 [%s] warnings but recieved [%d]", CodeDuplicateVariable, len(warnings))
+	}
+	if warnings[0].Params["type"] != varTypeContact || !reflect.DeepEqual(warnings[0].Indexes, []int{2, 4}) {
+		t.Errorf("Expected [%s] warning for contact on lines [2 4] but recieved [%s] on lines %v", CodeDuplicateVariable, warnings[0].Params["type"], warnings[0].Indexes)
+	}
+	if warnings[1].Params["type"] != varTypeOwnerDomain || !reflect.DeepEqual(warnings[1].Indexes, []int{5, 6}) {
+		t.Errorf("Expected [%s] warning for ownerdomain on lines [5 6] but recieved [%s] on lines %v", CodeDuplicateVariable, warnings[1].Params["type"], warnings[1].Indexes)
+	}
+}
+
+// TestDataRecordsRequired test validating Ads.txt file without data records
+func TestDataRecordsRequired(t *testing.T) {
+	files := map[string]bool{
+		"":                                     true,
+		"# comment only\ncontact=a@b.com":      true,
+		"greenadexchange.com, XF7342, UNKNOWN": true,
+		"greenadexchange.com, XF7342, DIRECT":  false,
+	}
+
+	for body, expected := range files {
+		res, _ := ParseBodyWithOptions([]byte(body), &ParseOptions{Registry: testRegistry, RequireDataRecords: true})
+		warnings := fileWarnings(res, CodeNoDataRecords)
+		if expected != (len(warnings) == 1) {
+			t.Errorf("Expected [%s] warning for [%s] to be [%t]", CodeNoDataRecords, body, expected)
+		}
+	}
+
+	// file without data records is not reported unless requested
+	res, _ := ParseBody([]byte("# comment only\ncontact=a@b.com"))
+	if len(res.Warnings) != 0 {
+		t.Errorf("Expected no warnings when [%s] rule is not requested but recieved [%d]", RuleDataRecordsRequired, len(res.Warnings))
+	}
+
+	res, _ = ParseBodyWithOptions([]byte(""), &ParseOptions{RequireDataRecords: true, DisabledRules: []string{RuleDataRecordsRequired}})
+	if len(res.Warnings) != 0 {
+		t.Errorf("Expected no warnings when [%s] rule is disabled but recieved [%d]", RuleDataRecordsRequired, len(res.Warnings))
+	}
+}
+
+// TestCustomFileRule test validating Ads.txt file using custom file level rule
+func TestCustomFileRule(t *testing.T) {
+	r := NewFileRule("max-records", func() FileChecker { return &maxRecords{max: 1} })
+
+	b := []byte("greenadexchange.com, XF7342, DIRECT\ngreenadexchange.com, XF7343, DIRECT")
+	res, _ := ParseBodyWithOptions(b, &ParseOptions{Registry: testRegistry, FileRules: []FileRule{r}, Severities: map[string]Sevirity{"ACME_TOO_MANY_RECORDS": ErrorSevirity}})
+
+	warnings := fileWarnings(res, "ACME_TOO_MANY_RECORDS")
+	if len(warnings) != 1 || warnings[0].Level != ErrorSevirity {
+		t.Errorf("Expected single custom file rule warning with [%s] sevirity but recieved [%v]", ErrorSevirity, warnings)
+	}
+
+	// checker is created for each parsed file
+	res, _ = ParseBodyWithOptions([]byte("greenadexchange.com, XF7342, DIRECT"), &ParseOptions{Registry: testRegistry, FileRules: []FileRule{r}})
+	if len(fileWarnings(res, "ACME_TOO_MANY_RECORDS")) != 0 {
+		t.Error("Expected no custom file rule warning for Ads.txt file with single data record")
+	}
+}
+
+// maxRecords FileChecker reporting Ads.txt file with too many data records
+type maxRecords struct {
+	max   int
+	count int
+}
+
+func (c *maxRecords) Observe(l *Line) {
+	if l.DataRecord != nil {
+		c.count++
+	}
+}
+
+func (c *maxRecords) Finish() []*Warning {
+	if c.count <= c.max {
+		return nil
+	}
+	return []*Warning{&Warning{Code: "ACME_TOO_MANY_RECORDS", Level: WarningSevirity, Message: "too many data records"}}
+}
@@ -0,0 +1,155 @@
+package adstxt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Fixes applied by Fix to Ads.txt file
+const (
+	// FixDomainScheme remove URL scheme (and path) from data record field #1
+	FixDomainScheme = "domain-scheme"
+	// FixLowercaseDomain normalize data record field #1: lowercase, IDNA ASCII form and no trailing dot
+	FixLowercaseDomain = "lowercase-domain"
+	// FixCanonicalDomain replace known ad system domain with the ad system canonical domain
+	FixCanonicalDomain = "canonical-domain"
+	// FixAccountType uppercase data record field #3
+	FixAccountType = "account-type"
+	// FixWhitespace normalize data record fields spacing
+	FixWhitespace = "whitespace"
+	// FixDuplicateRecord remove data record declared more than once
+	FixDuplicateRecord = "duplicate-record"
+)
+
+// Change single correction applied to Ads.txt file
+type Change struct {
+	Line        int    `json:"line"`           // Line number (1-based) of the changed line in the original Ads.txt file
+	Fix         string `json:"fix"`            // Fix applied to the line (e.g. FixCanonicalDomain)
+	Code        string `json:"code,omitempty"` // Code of the warning fixed by the change (if any)
+	Before      string `json:"before"`         // Before text of the line before the change
+	After       string `json:"after"`          // After text of the line after the change (empty if the line was removed)
+	Description string `json:"description"`    // Description of the change
+}
+
+// FixResult holds the fixed Ads.txt file, and the list of changes applied to it
+type FixResult struct {
+	Body    []byte    // Body fixed Ads.txt file content
+	Changes []*Change // Changes applied to the Ads.txt file, by line order
+}
+
+// Fix apply mechanical corrections to Ads.txt file: lowercase domains, replace non canonical ad system domains, remove
+// URL schemes from field #1, uppercase account types, normalize fields spacing and remove duplicate data records.
+// Lines are only altered if the fixed line is valid; lines which can not be confidently fixed are left as is.
+// Comments, blank lines and line endings are preserved
+func Fix(b []byte) *FixResult {
+	return FixWithOptions(b, nil)
+}
+
+// FixWithOptions apply mechanical corrections to Ads.txt file, using the specified parse options registry of known ad
+// systems to replace non canonical ad system domains and find duplicate data records, and its validation rules to
+// validate the fixed lines (data records of unknown ad systems are fixed as well)
+func FixWithOptions(b []byte, opts *ParseOptions) *FixResult {
+	o := ParseOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	o.KeepUnknownAdSystems = true
+
+	d := ParseDocument(b)
+	res := &FixResult{Changes: []*Change{}}
+	v := newParser(&o)
+	seen := map[string]bool{}
+	duplicates := map[int]bool{}
+
+	for i, l := range d.Lines {
+		if l.Kind != DataRecordLine {
+			continue
+		}
+
+		r, changes := fixDataRecord(l, v.registry)
+		if len(changes) > 0 {
+			// refuse to alter the line if the fixed line is still rejected by the validation rules
+			after := formatDataRecordLine(r)
+			if fl, _ := v.parseLine(i+1, after); fl == nil || fl.DataRecord == nil {
+				r = l.DataRecord
+			} else {
+				for _, c := range changes {
+					c.Line, c.Before, c.After = i+1, l.Raw, after
+				}
+				res.Changes = append(res.Changes, changes...)
+				d.ReplaceRecord(l.DataRecord, r)
+			}
+		}
+
+		// remove data record already declared, unless it carries its own comment
+		key := duplicateRecordKey(&Line{DataRecord: r, Registry: v.registry})
+		if seen[key] && len(r.Comment) == 0 {
+			res.Changes = append(res.Changes, &Change{
+				Line: i + 1, Fix: FixDuplicateRecord, Code: CodeDuplicateRecord, Before: l.Raw,
+				Description: fmt.Sprintf("Removed duplicate data record [%s]", l.Raw),
+			})
+			duplicates[i] = true
+		}
+		seen[key] = true
+	}
+
+	d.removeLines(func(i int, l *DocumentLine) bool { return duplicates[i] })
+	res.Body = d.Bytes()
+	return res
+}
+
+// fixDataRecord return copy of data record line record, with all applicable fixes applied (ad system domains are
+// canonicalized using the specified registry)
+func fixDataRecord(l *DocumentLine, reg *Registry) (*DataRecord, []*Change) {
+	r := *l.DataRecord
+	changes := []*Change{}
+
+	// remove URL scheme and path from ad system domain
+	if i := strings.Index(r.AdverterDomain, "://"); i != -1 {
+		domain := r.AdverterDomain[i+3:]
+		if j := strings.IndexAny(domain, "/?#"); j != -1 {
+			domain = domain[0:j]
+		}
+		if validateDomainName(domain) {
+			changes = append(changes, &Change{Fix: FixDomainScheme, Code: CodeInvalidAdSystemDomain,
+				Description: fmt.Sprintf("Replaced URL [%s] with domain [%s]", r.AdverterDomain, domain)})
+			r.AdverterDomain = domain
+		}
+	}
+
+	if normalized := normalizeDomain(r.AdverterDomain); normalized != r.AdverterDomain {
+		changes = append(changes, &Change{Fix: FixLowercaseDomain, Code: CodeNonNormalizedDomain,
+			Description: fmt.Sprintf("Replaced domain [%s] with normalized form [%s]", r.AdverterDomain, normalized)})
+		r.AdverterDomain = normalized
+	}
+
+	// only replace non canonical domain if the ad system declares a single canonical domain
+	if canonical, ok := reg.Canonicalize(r.AdverterDomain); ok && canonical != r.AdverterDomain {
+		changes = append(changes, &Change{Fix: FixCanonicalDomain, Code: CodeNonCanonicalAdSystem,
+			Description: fmt.Sprintf("Replaced domain [%s] with ad system canonical domain [%s]", r.AdverterDomain, canonical)})
+		r.AdverterDomain = canonical
+	}
+
+	// account type is uppercased by the parser, compare with the field as written in the line
+	content, _ := splitComment(l.Raw)
+	content, _ = splitExtension(content)
+	if fields := splitFields(content, ","); len(fields) > 2 && fields[2].value != r.AccountType {
+		changes = append(changes, &Change{Fix: FixAccountType,
+			Description: fmt.Sprintf("Replaced account type [%s] with [%s]", fields[2].value, r.AccountType)})
+	}
+
+	if len(changes) == 0 && formatDataRecordLine(&r) != l.Raw {
+		changes = append(changes, &Change{Fix: FixWhitespace, Description: "Normalized data record fields spacing"})
+	}
+
+	return &r, changes
+}
+
+// formatDataRecordLine return canonical Ads.txt line of data record, including its comment
+func formatDataRecordLine(r *DataRecord) string {
+	line := formatDataRecord(r)
+	if len(r.Comment) > 0 {
+		line += " " + commentDenote + " " + r.Comment
+	}
+	return line
+}
@@ -0,0 +1,97 @@
+package adstxt
+
+import (
+	"testing"
+)
+
+// TestFix test mechanical corrections applied to Ads.txt file
+func TestFix(t *testing.T) {
+	body := "# ads.txt\r\n" +
+		"Google.com, pub-1234, direct, f08c47fec0942fa0\r\n" +
+		"rubicon.com,5678,RESELLER # legacy\r\n" +
+		"https://www.pubmatic.com/ads, 9999, DIRECT\r\n" +
+		"google.com, pub-1234, DIRECT, f08c47fec0942fa0\r\n" +
+		"http://not a domain/, 1111, DIRECT\r\n" +
+		"openx.com, 2222, DIRECT\r\n" +
+		"contact=ads@example.com\r\n"
+
+	res := Fix([]byte(body))
+
+	expected := "# ads.txt\r\n" +
+		"google.com, pub-1234, DIRECT, f08c47fec0942fa0\r\n" +
+		"rubiconproject.com, 5678, RESELLER # legacy\r\n" +
+		"www.pubmatic.com, 9999, DIRECT\r\n" +
+		"http://not a domain/, 1111, DIRECT\r\n" +
+		"openx.com, 2222, DIRECT\r\n" +
+		"contact=ads@example.com\r\n"
+	if string(res.Body) != expected {
+		t.Errorf("Expected fixed Ads.txt file to be [%q] but recieved [%q]", expected, string(res.Body))
+	}
+
+	changes := []struct {
+		line int
+		fix  string
+	}{
+		{2, FixLowercaseDomain},
+		{2, FixAccountType},
+		{3, FixCanonicalDomain},
+		{4, FixDomainScheme},
+		{5, FixDuplicateRecord},
+	}
+	if len(res.Changes) != len(changes) {
+		t.Fatalf("Expected [%d] changes but recieved [%d]", len(changes), len(res.Changes))
+	}
+	for i, c := range changes {
+		if res.Changes[i].Line != c.line || res.Changes[i].Fix != c.fix {
+			t.Errorf("Expected change [%s] on line [%d] but recieved [%s] on line [%d]", c.fix, c.line, res.Changes[i].Fix, res.Changes[i].Line)
+		}
+	}
+
+	if c := res.Changes[2]; c.Before != "rubicon.com,5678,RESELLER # legacy" || c.After != "rubiconproject.com, 5678, RESELLER # legacy" {
+		t.Errorf("Expected change before and after text but recieved [%s] and [%s]", c.Before, c.After)
+	}
+}
+
+// TestFixWhitespace test normalizing data record fields spacing
+func TestFixWhitespace(t *testing.T) {
+	res := Fix([]byte("  openx.com ,\t2222,DIRECT;ext   \n"))
+	if string(res.Body) != "openx.com, 2222, DIRECT; ext\n" {
+		t.Errorf("Expected data record spacing to be normalized but recieved [%q]", string(res.Body))
+	}
+	if len(res.Changes) != 1 || res.Changes[0].Fix != FixWhitespace {
+		t.Errorf("Expected single whitespace change but recieved [%v]", res.Changes)
+	}
+
+	// Ads.txt file without anything to fix is left as is
+	body := "openx.com, 2222, DIRECT # comment\n\ncontact=ads@example.com"
+	res = Fix([]byte(body))
+	if string(res.Body) != body || len(res.Changes) != 0 {
+		t.Errorf("Expected Ads.txt file not to be changed but recieved [%q] and [%d] changes", string(res.Body), len(res.Changes))
+	}
+}
+
+// TestFixWithOptions test fixing Ads.txt file using custom registry of known ad systems
+func TestFixWithOptions(t *testing.T) {
+	r, err := NewRegistry([]*AdSystem{{ID: 1, Name: "Acme", CanonicalDomains: []string{"acme.com"}}},
+		[]*AdSystemDomain{{Domain: "acme.com", ID: 1}, {Domain: "acme.net", ID: 1}})
+	if err != nil {
+		t.Fatalf("Failed to create registry: %s", err)
+	}
+
+	body := "acme.net, 1, DIRECT\nacme.com, 1, DIRECT\nACME.net, 1, DIRECT"
+	if res := Fix([]byte(body)); string(res.Body) != "acme.net, 1, DIRECT\nacme.com, 1, DIRECT" {
+		t.Errorf("Expected unknown ad system domain not to be canonicalized but recieved [%q]", string(res.Body))
+	}
+
+	res := FixWithOptions([]byte(body), &ParseOptions{Registry: r})
+	if string(res.Body) != "acme.com, 1, DIRECT" {
+		t.Errorf("Expected ad system domain to be canonicalized using the registry but recieved [%q]", string(res.Body))
+	}
+
+	// duplicate data record change describe the removed line
+	for _, c := range res.Changes {
+		if c.Fix == FixDuplicateRecord && (c.Before != "acme.com, 1, DIRECT" || c.Description != "Removed duplicate data record ["+c.Before+"]") {
+			t.Errorf("Expected duplicate data record change to describe the removed line but recieved [%s] [%s]", c.Before, c.Description)
+		}
+	}
+}
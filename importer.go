@@ -0,0 +1,327 @@
+package adstxt
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// NormalizationMappingsURL IAB Ads.txt normalization mappings wiki page, listing known advertising systems and their
+// domains. Registry can be imported from a saved copy of this page using ImportRegistryHTML
+const NormalizationMappingsURL = "https://wiki.iabtechlab.com/index.php?title=Ads.txt_Normalization_Mappings"
+
+// Normalization mappings import error
+const (
+	errImportMissingTable = "Failed to import normalization mappings: [%s] table was not found"
+	errImportInvalidRow   = "Failed to import normalization mappings: [%s] table row [%d]: %s"
+)
+
+// normalization mappings tables names and columns
+const (
+	tableAdSystem       = "adsystem"
+	tableAdSystemDomain = "adsystem_domain"
+	columnID            = "ID"
+	columnName          = "NAME"
+	columnCanonical     = "CANONICAL_DOMAIN"
+	columnDomain        = "DOMAIN"
+)
+
+// ImportRegistryHTML import registry of known ad systems from a saved copy of the IAB normalization mappings wiki page
+// (see NormalizationMappingsURL). The page tables are identified by their header row: ad systems table declares
+// ID, NAME and CANONICAL_DOMAIN columns, and domains table declares DOMAIN and ID columns
+func ImportRegistryHTML(rd io.Reader) (*Registry, error) {
+	doc, err := html.Parse(rd)
+	if err != nil {
+		return nil, err
+	}
+
+	var adSystems []*AdSystem
+	var domains []*AdSystemDomain
+	for _, table := range htmlTables(doc) {
+		if len(table) == 0 {
+			continue
+		}
+
+		columns := tableColumns(table[0])
+		_, hasName := columns[columnName]
+		_, hasDomain := columns[columnDomain]
+		switch {
+		case adSystems == nil && hasName:
+			if adSystems, err = importAdSystems(table[1:], columns); err != nil {
+				return nil, err
+			}
+		case domains == nil && hasDomain:
+			if domains, err = importDomains(table[1:], columns); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if adSystems == nil {
+		return nil, fmt.Errorf(errImportMissingTable, tableAdSystem)
+	}
+	if domains == nil {
+		return nil, fmt.Errorf(errImportMissingTable, tableAdSystemDomain)
+	}
+	return NewRegistry(adSystems, domains)
+}
+
+// importAdSystems convert ad systems table rows into ad systems
+func importAdSystems(rows [][]string, columns map[string]int) ([]*AdSystem, error) {
+	adSystems := []*AdSystem{}
+	for i, row := range rows {
+		if isEmptyRow(row) {
+			continue
+		}
+
+		id, err := strconv.Atoi(cell(row, columns, columnID))
+		if err != nil {
+			return nil, fmt.Errorf(errImportInvalidRow, tableAdSystem, i+1, err.Error())
+		}
+
+		a := &AdSystem{ID: id, Name: cell(row, columns, columnName)}
+		if canonical := cell(row, columns, columnCanonical); len(canonical) > 0 && !strings.EqualFold(canonical, "NULL") {
+			a.CanonicalDomains = strings.Split(canonical, ",")
+		}
+		adSystems = append(adSystems, a)
+	}
+	return adSystems, nil
+}
+
+// importDomains convert domains table rows into ad systems domains
+func importDomains(rows [][]string, columns map[string]int) ([]*AdSystemDomain, error) {
+	domains := []*AdSystemDomain{}
+	for i, row := range rows {
+		if isEmptyRow(row) {
+			continue
+		}
+
+		id, err := strconv.Atoi(cell(row, columns, columnID))
+		if err != nil {
+			return nil, fmt.Errorf(errImportInvalidRow, tableAdSystemDomain, i+1, err.Error())
+		}
+		domains = append(domains, &AdSystemDomain{Domain: cell(row, columns, columnDomain), ID: id})
+	}
+	return domains, nil
+}
+
+// htmlTables return the text of all HTML tables cells, by table and row
+func htmlTables(n *html.Node) [][][]string {
+	tables := [][][]string{}
+	if n.Type == html.ElementNode && n.Data == "table" {
+		table := [][]string{}
+		walkHTML(n, "tr", func(tr *html.Node) {
+			row := []string{}
+			for c := tr.FirstChild; c != nil; c = c.NextSibling {
+				if c.Type == html.ElementNode && (c.Data == "td" || c.Data == "th") {
+					row = append(row, strings.TrimSpace(htmlText(c)))
+				}
+			}
+			table = append(table, row)
+		})
+		return append(tables, table)
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		tables = append(tables, htmlTables(c)...)
+	}
+	return tables
+}
+
+// walkHTML call f for every element of the specified tag under n
+func walkHTML(n *html.Node, tag string, f func(n *html.Node)) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == tag {
+			f(c)
+			continue
+		}
+		walkHTML(c, tag, f)
+	}
+}
+
+// htmlText return the text content of HTML node
+func htmlText(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(htmlText(c))
+	}
+	return b.String()
+}
+
+// tableColumns map table header row columns names (uppercase, white spaces replaced with underscore) to their index
+func tableColumns(header []string) map[string]int {
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.Join(strings.Fields(strings.ToUpper(name)), "_")] = i
+	}
+	return columns
+}
+
+// cell return the value of the specified column of table row
+func cell(row []string, columns map[string]int, column string) string {
+	if i, ok := columns[column]; ok && i < len(row) {
+		return strings.TrimSpace(row[i])
+	}
+	return ""
+}
+
+// isEmptyRow check if all table row cells are empty
+func isEmptyRow(row []string) bool {
+	for _, c := range row {
+		if len(c) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// MergeRegistryMetadata return new registry holding the ad systems and domains of the new registry, along with the
+// ad systems metadata of the current registry which is not part of the IAB normalization mappings (certification
+// authority IDs and account ID pattern). Metadata declared by the new registry is kept as is, metadata of ad systems
+// which are not part of the new registry is dropped
+func MergeRegistryMetadata(current *Registry, next *Registry) (*Registry, error) {
+	adSystems := []*AdSystem{}
+	domains := []*AdSystemDomain{}
+	for _, a := range next.AdSystems() {
+		m := &AdSystem{ID: a.ID, Name: a.Name, CanonicalDomains: a.CanonicalDomains, CertAuthorityIDs: a.CertAuthorityIDs,
+			AccountIDPattern: a.AccountIDPattern}
+		if c, ok := current.adSystems[a.ID]; ok {
+			if len(m.CertAuthorityIDs) == 0 {
+				m.CertAuthorityIDs = c.CertAuthorityIDs
+			}
+			if len(m.AccountIDPattern) == 0 {
+				m.AccountIDPattern = c.AccountIDPattern
+			}
+		}
+		adSystems = append(adSystems, m)
+
+		for _, domain := range a.Domains {
+			domains = append(domains, &AdSystemDomain{Domain: domain, ID: a.ID})
+		}
+	}
+	return NewRegistry(adSystems, domains)
+}
+
+// CanonicalChange ad system which canonical domains were changed
+type CanonicalChange struct {
+	ID     int      `json:"id"`     // ID of the ad system
+	Name   string   `json:"name"`   // Name of the ad system
+	Before []string `json:"before"` // Before canonical domains in the current registry
+	After  []string `json:"after"`  // After canonical domains in the new registry
+}
+
+// MetadataLoss ad system metadata of the current registry (see MergeRegistryMetadata) which the new registry lacks
+type MetadataLoss struct {
+	ID               int      `json:"id"`                         // ID of the ad system
+	Name             string   `json:"name"`                       // Name of the ad system
+	CertAuthorityIDs []string `json:"certAuthorityIds,omitempty"` // CertAuthorityIDs certification authority IDs which are not part of the new registry
+	AccountIDPattern string   `json:"accountIdPattern,omitempty"` // AccountIDPattern account ID pattern which is missing (or replaced) in the new registry
+}
+
+// RegistryDiff holds the differences between two registries of known ad systems
+type RegistryDiff struct {
+	NewAdSystems     []*AdSystem        `json:"newAdSystems"`     // NewAdSystems ad systems which are not part of the current registry
+	NewDomains       []*AdSystemDomain  `json:"newDomains"`       // NewDomains alias domains which are not part of the current registry
+	CanonicalChanges []*CanonicalChange `json:"canonicalChanges"` // CanonicalChanges ad systems which canonical domains were changed
+	MetadataLosses   []*MetadataLoss    `json:"metadataLosses"`   // MetadataLosses ad systems metadata which would be lost by replacing the current registry
+}
+
+// Empty check if there are no differences between the registries
+func (d *RegistryDiff) Empty() bool {
+	return len(d.NewAdSystems) == 0 && len(d.NewDomains) == 0 && len(d.CanonicalChanges) == 0 && len(d.MetadataLosses) == 0
+}
+
+// custom "toString" method
+func (d *RegistryDiff) String() string {
+	str := []string{}
+	for _, a := range d.NewAdSystems {
+		str = append(str, fmt.Sprintf("+ ad system [%d] [%s] canonical domains [%s]", a.ID, a.Name, strings.Join(a.CanonicalDomains, ", ")))
+	}
+	for _, d := range d.NewDomains {
+		str = append(str, fmt.Sprintf("+ domain [%s] of ad system [%d]", d.Domain, d.ID))
+	}
+	for _, c := range d.CanonicalChanges {
+		str = append(str, fmt.Sprintf("~ ad system [%d] [%s] canonical domains [%s] => [%s]", c.ID, c.Name,
+			strings.Join(c.Before, ", "), strings.Join(c.After, ", ")))
+	}
+	for _, l := range d.MetadataLosses {
+		str = append(str, fmt.Sprintf("- ad system [%d] [%s] cert authority IDs [%s] account ID pattern [%s]", l.ID, l.Name,
+			strings.Join(l.CertAuthorityIDs, ", "), l.AccountIDPattern))
+	}
+	return strings.Join(str, "\n")
+}
+
+// DiffRegistry report the differences of the new registry compared to the current registry: new ad systems, new
+// alias domains, canonical domains changes and ad systems metadata which the new registry lacks
+func DiffRegistry(current *Registry, next *Registry) *RegistryDiff {
+	d := &RegistryDiff{NewAdSystems: []*AdSystem{}, NewDomains: []*AdSystemDomain{}, CanonicalChanges: []*CanonicalChange{},
+		MetadataLosses: []*MetadataLoss{}}
+
+	for _, a := range next.AdSystems() {
+		c, ok := current.adSystems[a.ID]
+		if !ok {
+			d.NewAdSystems = append(d.NewAdSystems, a)
+		} else if !equalDomains(c.CanonicalDomains, a.CanonicalDomains) {
+			d.CanonicalChanges = append(d.CanonicalChanges, &CanonicalChange{ID: a.ID, Name: a.Name, Before: c.CanonicalDomains, After: a.CanonicalDomains})
+		}
+
+		for _, domain := range a.Domains {
+			if _, ok := current.domains[domain]; !ok {
+				d.NewDomains = append(d.NewDomains, &AdSystemDomain{Domain: domain, ID: a.ID})
+			}
+		}
+	}
+
+	for _, c := range current.AdSystems() {
+		if l := lostMetadata(c, next.adSystems[c.ID]); l != nil {
+			d.MetadataLosses = append(d.MetadataLosses, l)
+		}
+	}
+
+	return d
+}
+
+// lostMetadata return the metadata of the current ad system which the new ad system (nil if removed) lacks, nil if
+// no metadata is lost
+func lostMetadata(current *AdSystem, next *AdSystem) *MetadataLoss {
+	l := &MetadataLoss{ID: current.ID, Name: current.Name}
+	for _, id := range current.CertAuthorityIDs {
+		if next == nil || !next.isCertAuthorityID(id) {
+			l.CertAuthorityIDs = append(l.CertAuthorityIDs, id)
+		}
+	}
+	if len(current.AccountIDPattern) > 0 && (next == nil || next.AccountIDPattern != current.AccountIDPattern) {
+		l.AccountIDPattern = current.AccountIDPattern
+	}
+
+	if len(l.CertAuthorityIDs) == 0 && len(l.AccountIDPattern) == 0 {
+		return nil
+	}
+	return l
+}
+
+// equalDomains check if both lists hold the same domains, regardless of order
+func equalDomains(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	sa := append([]string{}, a...)
+	sb := append([]string{}, b...)
+	sort.Strings(sa)
+	sort.Strings(sb)
+	for i := range sa {
+		if sa[i] != sb[i] {
+			return false
+		}
+	}
+	return true
+}
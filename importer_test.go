@@ -0,0 +1,188 @@
+package adstxt
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// testMappingsPage saved copy of the IAB normalization mappings wiki page (stripped down)
+const testMappingsPage = `<html><body><div id="content">
+<h2>adsystem</h2>
+<table class="wikitable">
+<tr><th>ID</th><th>NAME</th><th>CANONICAL_DOMAIN</th></tr>
+<tr><td>1</td><td>Rubicon Project</td><td>rubiconproject.com</td></tr>
+<tr><td>8</td><td>Google</td><td>google.com</td></tr>
+<tr><td>11</td><td>One by AOL: Display</td><td>adtech.com, aolcloud.net</td></tr>
+<tr><td>500</td><td><a href="/acme">Acme Exchange</a></td><td>NULL</td></tr>
+</table>
+<h2>adsystem_domain</h2>
+<table class="wikitable">
+<tr><th>DOMAIN</th><th>ID</th></tr>
+<tr><td>rubicon.com</td><td>1</td></tr>
+<tr><td>Google.com</td><td>8</td></tr>
+<tr><td>acme-exchange.com</td><td>500</td></tr>
+<tr><td></td><td></td></tr>
+</table>
+</div></body></html>`
+
+// TestImportRegistryHTML test importing registry from the IAB normalization mappings wiki page
+func TestImportRegistryHTML(t *testing.T) {
+	r, err := ImportRegistryHTML(strings.NewReader(testMappingsPage))
+	if err != nil {
+		t.Fatalf("Failed to import registry: %s", err)
+	}
+
+	adSystems := r.AdSystems()
+	if len(adSystems) != 4 {
+		t.Fatalf("Expected [4] ad systems but recieved [%d]", len(adSystems))
+	}
+	if a := adSystems[3]; a.ID != 500 || a.Name != "Acme Exchange" || len(a.CanonicalDomains) != 0 || len(a.Domains) != 1 {
+		t.Errorf("Expected [Acme Exchange] ad system without canonical domain but recieved [%v]", a)
+	}
+	if a := adSystems[2]; len(a.CanonicalDomains) != 2 || a.CanonicalDomains[1] != "aolcloud.net" {
+		t.Errorf("Expected [adtech.com aolcloud.net] canonical domains but recieved [%v]", a.CanonicalDomains)
+	}
+	if err := r.validate("rubicon.com"); err == nil {
+		t.Error("Expected [rubicon.com] to be non canonical domain")
+	}
+
+	if _, err := ImportRegistryHTML(strings.NewReader("<html><body><table><tr><th>DOMAIN</th><th>ID</th></tr></table></body></html>")); err == nil {
+		t.Error("Expected error when importing page without ad systems table")
+	}
+}
+
+// TestDiffRegistry test reporting the differences between registries
+func TestDiffRegistry(t *testing.T) {
+	current, _ := NewRegistry([]*AdSystem{
+		{ID: 1, Name: "Rubicon Project", CanonicalDomains: []string{"rubiconproject.com"}},
+		{ID: 11, Name: "One by AOL: Display", CanonicalDomains: []string{"aolcloud.net", "adtech.com"}},
+		{ID: 8, Name: "Google"},
+	}, []*AdSystemDomain{{Domain: "rubicon.com", ID: 1}})
+
+	next, _ := ImportRegistryHTML(strings.NewReader(testMappingsPage))
+	d := DiffRegistry(current, next)
+
+	if len(d.NewAdSystems) != 1 || d.NewAdSystems[0].ID != 500 {
+		t.Errorf("Expected ad system [500] to be new but recieved [%v]", d.NewAdSystems)
+	}
+	if len(d.NewDomains) != 2 || d.NewDomains[0].Domain != "google.com" || d.NewDomains[1].Domain != "acme-exchange.com" {
+		t.Errorf("Expected domains [google.com acme-exchange.com] to be new but recieved [%v]", d.NewDomains)
+	}
+	if len(d.CanonicalChanges) != 1 || d.CanonicalChanges[0].ID != 8 || d.CanonicalChanges[0].After[0] != "google.com" {
+		t.Errorf("Expected ad system [8] canonical domains to be changed but recieved [%v]", d.CanonicalChanges)
+	}
+	if d.Empty() || !DiffRegistry(next, next).Empty() {
+		t.Error("Expected registry to differ from current registry only")
+	}
+}
+
+// TestMergeRegistryMetadata test imported registry keeps the metadata of the current registry, and that metadata
+// which would be lost is reported
+func TestMergeRegistryMetadata(t *testing.T) {
+	current, _ := NewRegistry([]*AdSystem{
+		{ID: 1, Name: "Rubicon Project", CanonicalDomains: []string{"rubiconproject.com"}, CertAuthorityIDs: []string{"0bfd66d529a55807"}},
+		{ID: 8, Name: "Google", CanonicalDomains: []string{"google.com"}, CertAuthorityIDs: []string{"f08c47fec0942fa0"}, AccountIDPattern: `pub-\d{16}`},
+		{ID: 9, Name: "Removed", AccountIDPattern: `\d+`},
+	}, nil)
+
+	next, _ := ImportRegistryHTML(strings.NewReader(testMappingsPage))
+	d := DiffRegistry(current, next)
+	if len(d.MetadataLosses) != 3 || d.MetadataLosses[1].ID != 8 || d.MetadataLosses[1].AccountIDPattern != `pub-\d{16}` ||
+		len(d.MetadataLosses[1].CertAuthorityIDs) != 1 {
+		t.Errorf("Expected metadata of ad systems [1 8 9] to be reported as lost but recieved [%v]", d.MetadataLosses)
+	}
+
+	merged, err := MergeRegistryMetadata(current, next)
+	if err != nil {
+		t.Fatalf("Failed to merge registry metadata: %s", err)
+	}
+	if a, _ := merged.LookupID(8); !a.isCertAuthorityID("f08c47fec0942fa0") || a.AccountIDPattern != `pub-\d{16}` || a.matchAccountID("pub-1") {
+		t.Errorf("Expected [Google] ad system to keep its metadata but recieved [%v]", a)
+	}
+	if a, _ := merged.LookupID(1); !a.isCertAuthorityID("0bfd66d529a55807") {
+		t.Errorf("Expected [Rubicon Project] ad system to keep its certification authority IDs but recieved [%v]", a)
+	}
+
+	// only metadata of ad systems which are not part of the new registry is lost
+	d = DiffRegistry(current, merged)
+	if len(d.MetadataLosses) != 1 || d.MetadataLosses[0].ID != 9 {
+		t.Errorf("Expected only metadata of removed ad system [9] to be lost but recieved [%v]", d.MetadataLosses)
+	}
+	if len(d.NewAdSystems) != 2 || len(d.NewDomains) != 3 {
+		t.Errorf("Expected merged registry to hold the ad systems and domains of the imported registry but recieved [%v]", d)
+	}
+}
+
+// TestImportRegistryKeepsMetadata test refreshing the default registry from the normalization mappings tables keeps
+// the certification authority IDs and account ID patterns the validation rules depend on
+func TestImportRegistryKeepsMetadata(t *testing.T) {
+	// normalization mappings tables of the default registry, as exported from the wiki page (without metadata)
+	var adSystems, domains strings.Builder
+	adSystems.WriteString("ID,NAME,CANONICAL_DOMAIN\n")
+	domains.WriteString("DOMAIN,ID\n")
+	for _, a := range DefaultRegistry().AdSystems() {
+		canonical := "NULL"
+		if len(a.CanonicalDomains) > 0 {
+			canonical = strings.Join(a.CanonicalDomains, ",")
+		}
+		fmt.Fprintf(&adSystems, "%d,%q,%q\n", a.ID, a.Name, canonical)
+		for _, d := range a.Domains {
+			fmt.Fprintf(&domains, "%q,%d\n", d, a.ID)
+		}
+	}
+
+	next, err := LoadRegistryCSV(strings.NewReader(adSystems.String()), strings.NewReader(domains.String()))
+	if err != nil {
+		t.Fatalf("Failed to import registry: %s", err)
+	}
+	merged, err := MergeRegistryMetadata(DefaultRegistry(), next)
+	if err != nil {
+		t.Fatalf("Failed to merge registry metadata: %s", err)
+	}
+
+	withMetadata := 0
+	for _, a := range DefaultRegistry().AdSystems() {
+		m, ok := merged.LookupID(a.ID)
+		if !ok || !equalDomains(m.CertAuthorityIDs, a.CertAuthorityIDs) || m.AccountIDPattern != a.AccountIDPattern {
+			t.Errorf("Expected ad system [%d] [%s] to keep its metadata but recieved [%v]", a.ID, a.Name, m)
+		}
+		if len(a.CertAuthorityIDs) > 0 || len(a.AccountIDPattern) > 0 {
+			withMetadata++
+		}
+	}
+	if withMetadata == 0 {
+		t.Error("Expected default registry to hold ad systems metadata")
+	}
+	if d := DiffRegistry(DefaultRegistry(), merged); !d.Empty() {
+		t.Errorf("Expected refreshed registry not to differ from the default registry but recieved [%s]", d)
+	}
+
+	// validation rules depending on the metadata still apply using the refreshed registry
+	res, _ := ParseBodyWithOptions([]byte("google.com, pub-1234, DIRECT, 5jyxf8k"), &ParseOptions{Registry: merged})
+	codes := map[string]bool{}
+	for _, w := range res.Warnings {
+		codes[w.Code] = true
+	}
+	if !codes[CodeCertAuthorityIDMismatch] || !codes[CodeInvalidAccountIDFormat] {
+		t.Errorf("Expected [%s] and [%s] warnings using the refreshed registry but recieved [%v]", CodeCertAuthorityIDMismatch, CodeInvalidAccountIDFormat, res.Warnings)
+	}
+}
+
+// TestRegistryWriteJSON test that written registry is loaded back as is
+func TestRegistryWriteJSON(t *testing.T) {
+	var b bytes.Buffer
+	if err := DefaultRegistry().WriteJSON(&b); err != nil {
+		t.Fatalf("Failed to write registry: %s", err)
+	}
+
+	if b.String() != string(defaultRegistryData) {
+		t.Error("Expected default registry to be written as its embedded data")
+	}
+
+	r, err := LoadRegistry(&b)
+	if err != nil || !DiffRegistry(DefaultRegistry(), r).Empty() || !DiffRegistry(r, DefaultRegistry()).Empty() {
+		t.Errorf("Expected written registry to be loaded back as is (%v)", err)
+	}
+}
@@ -0,0 +1,142 @@
+package adstxt
+
+// ParseOptions control how Ads.txt file is parsed and validated
+type ParseOptions struct {
+	DisabledRules []string            // DisabledRules names of validation rules (built-in or custom) which should not run
+	Severities    map[string]Sevirity // Severities override the sevirity level of warnings, by warning code
+	Rules         []Rule              // Rules custom validation rules, run after the built-in rules
+	FileRules     []FileRule          // FileRules custom file level validation rules, run after the built-in file level rules
+	Strict        bool                // Strict fail parsing with ValidationError if any warning is an error (ErrorSevirity or above)
+	Registry      *Registry           // Registry of known ad systems used to validate data records (DefaultRegistry if not set)
+	MaxLineLength int                 // MaxLineLength maximum length of Ads.txt line in bytes (DefaultMaxLineLength if not set, no limit if negative)
+	DiscardBody   bool                // DiscardBody do not keep the original Ads.txt file content in Records.Body
+	Domain        string              // Domain root domain of the Ads.txt file, used to validate subdomain variables scope (not validated if empty)
+	Tolerant      bool                // Tolerant repair common data record format mistakes (quotes, delimiters, URL as field #1) instead of rejecting the record
+
+	ExtensionParsers map[string]ExtensionParser // ExtensionParsers extension parsers by ad system domain, override the parsers registered by RegisterExtensionParser
+
+	RequireDataRecords   bool // RequireDataRecords report Ads.txt file without any valid data record (data-records-required file rule)
+	KeepUnknownAdSystems bool // KeepUnknownAdSystems keep data records of unknown ad systems or declared using non canonical ad system domain (rejected by default)
+	IncludeSuppressed    bool // IncludeSuppressed keep warnings suppressed by "adstxt:ignore" comment directives in Records.Suppressed
+	SuppressErrors       bool // SuppressErrors allow "adstxt:ignore" comment directives to suppress errors: records are kept and strict mode does not fail
+}
+
+// rules return the validation rules enabled by the parse options
+func (o *ParseOptions) rules() []Rule {
+	if o == nil {
+		return builtinRules
+	}
+
+	disabled := make(map[string]bool, len(o.DisabledRules))
+	for _, name := range o.DisabledRules {
+		disabled[name] = true
+	}
+
+	rules := []Rule{}
+	for _, r := range append(builtinRules[:len(builtinRules):len(builtinRules)], o.Rules...) {
+		if !disabled[r.Name()] {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// fileCheckers return new checkers of the file level validation rules enabled by the parse options
+func (o *ParseOptions) fileCheckers() []FileChecker {
+	rules := builtinFileRules
+	disabled := map[string]bool{}
+	if o != nil {
+		rules = builtinFileRules[:len(builtinFileRules):len(builtinFileRules)]
+		if o.RequireDataRecords {
+			rules = append(rules, dataRecordsRequiredRule)
+		}
+		rules = append(rules, o.FileRules...)
+		for _, name := range o.DisabledRules {
+			disabled[name] = true
+		}
+	}
+
+	checkers := []FileChecker{}
+	for _, r := range rules {
+		if !disabled[r.Name()] {
+			checkers = append(checkers, r.NewChecker())
+		}
+	}
+	return checkers
+}
+
+// registry return the registry of known ad systems used by the parse options
+func (o *ParseOptions) registry() *Registry {
+	if o == nil || o.Registry == nil {
+		return defaultRegistry
+	}
+	return o.Registry
+}
+
+// extensionParsers return the extension parsers used by the parse options: registered parsers, overridden by the
+// parse options parsers, applied to all known domains of their ad system
+func (o *ParseOptions) extensionParsers() *extensionParserSet {
+	if o == nil {
+		return newExtensionParserSet(defaultRegistry, nil)
+	}
+	return newExtensionParserSet(o.registry(), o.ExtensionParsers)
+}
+
+// domain return the normalized root domain of the Ads.txt file, empty if unknown
+func (o *ParseOptions) domain() string {
+	if o == nil {
+		return ""
+	}
+	return normalizeDomain(o.Domain)
+}
+
+// withDomain return copy of the parse options with the root domain of the Ads.txt file set, unless it is already set
+func (o *ParseOptions) withDomain(domain string) *ParseOptions {
+	opts := ParseOptions{}
+	if o != nil {
+		opts = *o
+	}
+	if len(opts.Domain) == 0 {
+		opts.Domain = domain
+	}
+	return &opts
+}
+
+// suppressErrors return true if comment directives may suppress errors
+func (o *ParseOptions) suppressErrors() bool {
+	return o != nil && o.SuppressErrors
+}
+
+// maxLineLength return the maximum length of Ads.txt line, 0 if line length is not limited
+func (o *ParseOptions) maxLineLength() int {
+	switch {
+	case o == nil || o.MaxLineLength == 0:
+		return DefaultMaxLineLength
+	case o.MaxLineLength < 0:
+		return 0
+	}
+	return o.MaxLineLength
+}
+
+// rejects return true if the warning rejects the record of the line it was found on: errors (ErrorSevirity or above)
+// are always rejected, as are unknown ad systems and non canonical ad system domains unless the parse options request
+// to keep them
+func (o *ParseOptions) rejects(w *Warning) bool {
+	if w.Level >= ErrorSevirity {
+		return true
+	}
+	if w.Code == CodeUnknownAdSystem || w.Code == CodeNonCanonicalAdSystem {
+		return o == nil || !o.KeepUnknownAdSystems
+	}
+	return false
+}
+
+// sevirity return the sevirity level of the warning, after applying the parse options overrides
+func (o *ParseOptions) sevirity(w *Warning) Sevirity {
+	if o != nil {
+		if level, ok := o.Severities[w.Code]; ok {
+			return level
+		}
+	}
+	return w.Level
+}
@@ -0,0 +1,219 @@
+package adstxt
+
+import (
+	"strings"
+)
+
+// RecordsStats summary of Ads.txt file data records
+type RecordsStats struct {
+	DataRecords int `json:"dataRecords"` // DataRecords number of data records
+	Direct      int `json:"direct"`      // Direct number of DIRECT data records
+	Reseller    int `json:"reseller"`    // Reseller number of RESELLER data records
+	AdSystems   int `json:"adSystems"`   // AdSystems number of distinct ad systems (all known domains of an ad system count once)
+}
+
+// Filter return new view of the records holding only the data records for which keep returns true. Data records are
+// shared with the original records and keep their line provenance (Line, Raw and Comment), all variables and Body
+// lines are kept (in slices of their own), and only the warnings concerning the lines of the kept data records and of
+// the variables are kept
+func (r *Records) Filter(keep func(dr *DataRecord) bool) *Records {
+	view := r.newView()
+
+	lines := r.variableLines()
+	for _, dr := range r.DataRecords {
+		if keep(dr) {
+			view.DataRecords = append(view.DataRecords, dr)
+			if dr.Line > 0 {
+				lines[dr.Line] = true
+			}
+		}
+	}
+
+	view.Warnings = filterWarnings(r.Warnings, lines)
+	if r.Suppressed != nil {
+		view.Suppressed = filterWarnings(r.Suppressed, lines)
+	}
+	return view
+}
+
+// newView return new view of the records without data records and warnings, holding copies of the variables and Body
+func (r *Records) newView() *Records {
+	return &Records{
+		DataRecords: []*DataRecord{},
+		Variables:   append([]*Variable{}, r.Variables...),
+		Warnings:    []*Warning{},
+		Body:        append([]string{}, r.Body...),
+	}
+}
+
+// variableLines return the lines the variables are declared on
+func (r *Records) variableLines() map[int]bool {
+	lines := map[int]bool{}
+	for _, v := range r.Variables {
+		if v.Line > 0 {
+			lines[v.Line] = true
+		}
+	}
+	return lines
+}
+
+// FilterAdSystem return new view of the records holding only the data records of the ad system of the specified domain:
+// data records declaring any of the ad system known domains (canonical domain or alias, in any of its forms) are kept.
+// Ad system domains are looked up in the specified registry (DefaultRegistry if nil), unknown domains match only
+// themselves
+func (r *Records) FilterAdSystem(domain string, reg *Registry) *Records {
+	reg = registryOrDefault(reg)
+	key := reg.key(domain)
+	return r.Filter(func(dr *DataRecord) bool {
+		return reg.key(dr.AdverterDomain) == key
+	})
+}
+
+// FilterAccountType return new view of the records holding only the data records of the specified account type
+// (DIRECT or RESELLER, case insensitive)
+func (r *Records) FilterAccountType(accountType string) *Records {
+	accountType = strings.TrimSpace(accountType)
+	return r.Filter(func(dr *DataRecord) bool {
+		return strings.EqualFold(dr.AccountType, accountType)
+	})
+}
+
+// FilterAccountID return new view of the records holding only the data records of the specified publisher account ID
+func (r *Records) FilterAccountID(id string) *Records {
+	id = strings.TrimSpace(id)
+	return r.Filter(func(dr *DataRecord) bool {
+		return dr.PublisherAccountID == id
+	})
+}
+
+// FilterCertAuthorityID return new view of the records holding only the data records of the specified certification
+// authority ID (case insensitive)
+func (r *Records) FilterCertAuthorityID(id string) *Records {
+	id = strings.TrimSpace(id)
+	return r.Filter(func(dr *DataRecord) bool {
+		return strings.EqualFold(dr.CertAuthorityID, id)
+	})
+}
+
+// GroupByAdSystem return views of the records grouped by ad system, mapped to the ad system canonical domain. All
+// known domains of an ad system are grouped together (using the specified registry, DefaultRegistry if nil), and
+// unknown domains and ad systems without canonical domain are mapped to the normalized domain of their first data
+// record. Ad systems mapped to the same domain (e.g. ad system without canonical domain declared using the canonical
+// domain of another ad system) share a single group. Each group holds the warnings concerning its data records and the
+// variables, as returned by Filter
+func (r *Records) GroupByAdSystem(reg *Registry) map[string]*Records {
+	reg = registryOrDefault(reg)
+
+	// group data records in a single pass: the group of each ad system is named after its first data record
+	groups := map[string]*Records{}
+	names := map[string]string{}
+	lines := map[int]string{}
+	for _, dr := range r.DataRecords {
+		key := reg.key(dr.AdverterDomain)
+		name, ok := names[key]
+		if !ok {
+			name = reg.canonicalName(dr.AdverterDomain)
+			names[key] = name
+		}
+
+		g, ok := groups[name]
+		if !ok {
+			g = r.newView()
+			groups[name] = g
+		}
+		g.DataRecords = append(g.DataRecords, dr)
+		if dr.Line > 0 {
+			lines[dr.Line] = name
+		}
+	}
+
+	shared := r.variableLines()
+	groupWarnings(groups, r.Warnings, lines, shared, func(g *Records, w *Warning) { g.Warnings = append(g.Warnings, w) })
+	if r.Suppressed != nil {
+		for _, g := range groups {
+			g.Suppressed = []*Warning{}
+		}
+		groupWarnings(groups, r.Suppressed, lines, shared, func(g *Records, w *Warning) { g.Suppressed = append(g.Suppressed, w) })
+	}
+	return groups
+}
+
+// groupWarnings add each warning to the groups of the lines it concerns (data record lines mapped to their group),
+// warnings concerning shared lines (variables) are added to all groups
+func groupWarnings(groups map[string]*Records, warnings []*Warning, lines map[int]string, shared map[int]bool, add func(g *Records, w *Warning)) {
+	for _, w := range warnings {
+		indexes := append([]int{w.Index}, w.Indexes...)
+
+		all := false
+		names := map[string]bool{}
+		for _, i := range indexes {
+			all = all || shared[i]
+			if name, ok := lines[i]; ok {
+				names[name] = true
+			}
+		}
+
+		if all {
+			for _, g := range groups {
+				add(g, w)
+			}
+			continue
+		}
+		for name := range names {
+			add(groups[name], w)
+		}
+	}
+}
+
+// Stats return summary of the data records: number of DIRECT and RESELLER data records, and number of distinct ad
+// systems (using the specified registry to group all known domains of an ad system, DefaultRegistry if nil)
+func (r *Records) Stats(reg *Registry) RecordsStats {
+	reg = registryOrDefault(reg)
+
+	stats := RecordsStats{DataRecords: len(r.DataRecords)}
+	adSystems := map[string]bool{}
+	for _, dr := range r.DataRecords {
+		switch strings.ToUpper(dr.AccountType) {
+		case accountTypeDirect:
+			stats.Direct++
+		case accountTypeReseller:
+			stats.Reseller++
+		}
+		adSystems[reg.key(dr.AdverterDomain)] = true
+	}
+	stats.AdSystems = len(adSystems)
+	return stats
+}
+
+// filterWarnings return the warnings concerning the specified lines: line warnings found on one of the lines, and file
+// level warnings involving any of the lines
+func filterWarnings(warnings []*Warning, lines map[int]bool) []*Warning {
+	filtered := []*Warning{}
+	for _, w := range warnings {
+		keep := lines[w.Index]
+		for _, i := range w.Indexes {
+			keep = keep || lines[i]
+		}
+		if keep {
+			filtered = append(filtered, w)
+		}
+	}
+	return filtered
+}
+
+// registryOrDefault return the specified registry, or the default registry if nil
+func registryOrDefault(reg *Registry) *Registry {
+	if reg == nil {
+		return defaultRegistry
+	}
+	return reg
+}
+
+// canonicalName return the canonical domain of the ad system of the specified domain (the first one, if the ad system
+// declares multiple canonical domains), or the normalized domain if unknown or the ad system has no canonical domain
+func (r *Registry) canonicalName(domain string) string {
+	if a, ok := r.Lookup(domain); ok && len(a.CanonicalDomains) > 0 {
+		return a.CanonicalDomains[0]
+	}
+	return normalizeDomain(domain)
+}
@@ -0,0 +1,173 @@
+package adstxt
+
+import (
+	"testing"
+)
+
+// queryBody Ads.txt file used by records query tests
+const queryBody = "google.com, pub-0000000000001234, DIRECT, f08c47fec0942fa0\n" +
+	"googletagservices.com, pub-0000000000005678, RESELLER, f08c47fec0942fa0\n" +
+	"rubiconproject.com, 12345, DIRECT, 0bfd66d529a55807\n" +
+	"rubicon.com, 12345, RESELLER\n" +
+	"example.com, 12345, Direct, abc123\n" +
+	"contact=adops@example.com\n"
+
+// TestRecordsFilter test filtering data records by ad system, account type, account ID and certification authority ID
+func TestRecordsFilter(t *testing.T) {
+	rec, err := ParseBodyWithOptions([]byte(queryBody), &ParseOptions{Registry: testRegistry, KeepUnknownAdSystems: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		view  *Records
+		lines []int
+	}{
+		{"google.com", rec.FilterAdSystem("google.com", testRegistry), []int{1, 2}},
+		{"GoogleTagServices.com", rec.FilterAdSystem("GoogleTagServices.com", nil), []int{1, 2}},
+		{"rubicon.com", rec.FilterAdSystem("rubicon.com", testRegistry), []int{3, 4}},
+		{"example.com", rec.FilterAdSystem("EXAMPLE.com", testRegistry), []int{5}},
+		{"unknown.com", rec.FilterAdSystem("unknown.com", testRegistry), []int{}},
+		{"DIRECT", rec.FilterAccountType("direct"), []int{1, 3, 5}},
+		{"RESELLER", rec.FilterAccountType("RESELLER"), []int{2, 4}},
+		{"12345", rec.FilterAccountID("12345"), []int{3, 4, 5}},
+		{"f08c47fec0942fa0", rec.FilterCertAuthorityID("F08C47FEC0942FA0"), []int{1, 2}},
+		{"no cert", rec.FilterCertAuthorityID(""), []int{4}},
+	}
+
+	for _, test := range tests {
+		if len(test.view.DataRecords) != len(test.lines) {
+			t.Errorf("Expected [%s] filter to return [%d] data records but recieved [%d]", test.name, len(test.lines), len(test.view.DataRecords))
+			continue
+		}
+		for i, dr := range test.view.DataRecords {
+			if dr.Line != test.lines[i] || dr.Raw != rec.Body[dr.Line-1] {
+				t.Errorf("Expected [%s] filter data record [%d] to be declared on line [%d] but recieved [%d]", test.name, i+1, test.lines[i], dr.Line)
+			}
+		}
+		if len(test.view.Variables) != 1 || len(test.view.Body) != len(rec.Body) {
+			t.Errorf("Expected [%s] filter to keep variables and Ads.txt file content", test.name)
+		}
+	}
+
+	// only warnings concerning the kept data records are kept
+	if len(rec.Warnings) == 0 {
+		t.Fatalf("Expected Ads.txt file to have warnings")
+	}
+	for _, w := range rec.FilterAdSystem("example.com", testRegistry).Warnings {
+		if w.Index != 5 {
+			t.Errorf("Expected only warnings of line [5] but recieved warning of line [%d] [%s]", w.Index, w.Code)
+		}
+	}
+	conflicting := 0
+	for _, w := range rec.FilterAdSystem("rubicon.com", testRegistry).Warnings {
+		if w.Code == CodeConflictingAccountType {
+			conflicting++
+		}
+	}
+	if conflicting != 1 {
+		t.Errorf("Expected file level [%s] warning to be kept", CodeConflictingAccountType)
+	}
+}
+
+// TestRecordsGroupByAdSystem test grouping data records by ad system canonical domain
+func TestRecordsGroupByAdSystem(t *testing.T) {
+	rec, _ := ParseBodyWithOptions([]byte(queryBody), &ParseOptions{Registry: testRegistry, KeepUnknownAdSystems: true})
+	groups := rec.GroupByAdSystem(testRegistry)
+
+	expected := map[string]int{"google.com": 2, "rubiconproject.com": 2, "example.com": 1}
+	if len(groups) != len(expected) {
+		t.Errorf("Expected [%d] ad system groups but recieved [%d]", len(expected), len(groups))
+	}
+	for domain, count := range expected {
+		if g, ok := groups[domain]; !ok || len(g.DataRecords) != count {
+			t.Errorf("Expected [%s] group to have [%d] data records", domain, count)
+		}
+	}
+}
+
+// TestRecordsStats test data records summary
+func TestRecordsStats(t *testing.T) {
+	rec, _ := ParseBodyWithOptions([]byte(queryBody), &ParseOptions{Registry: testRegistry, KeepUnknownAdSystems: true})
+
+	expected := RecordsStats{DataRecords: 5, Direct: 3, Reseller: 2, AdSystems: 3}
+	if stats := rec.Stats(testRegistry); stats != expected {
+		t.Errorf("Expected records stats to be [%+v] but recieved [%+v]", expected, stats)
+	}
+	if stats := newRecords().Stats(nil); stats != (RecordsStats{}) {
+		t.Errorf("Expected empty records stats but recieved [%+v]", stats)
+	}
+}
+
+// TestRecordsGroupByAdSystemCollision test ad systems mapped to the same domain share a single group
+func TestRecordsGroupByAdSystemCollision(t *testing.T) {
+	reg, err := NewRegistry([]*AdSystem{{ID: 1, Name: "Acme", CanonicalDomains: []string{"shared.com"}}, {ID: 2, Name: "Beta"}},
+		[]*AdSystemDomain{{Domain: "acme.com", ID: 1}, {Domain: "shared.com", ID: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := "acme.com, 1, DIRECT\nshared.com, 2, DIRECT\nacme.com, 3, RESELLER\n"
+	rec, _ := ParseBodyWithOptions([]byte(body), &ParseOptions{Registry: reg, KeepUnknownAdSystems: true})
+	groups := rec.GroupByAdSystem(reg)
+	if g, ok := groups["shared.com"]; len(groups) != 1 || !ok || len(g.DataRecords) != 3 {
+		t.Errorf("Expected single [shared.com] group with [3] data records but recieved [%v]", groups)
+	}
+}
+
+// TestRecordsFilterVariables test filtered views do not share the variables of the original records
+func TestRecordsFilterVariables(t *testing.T) {
+	rec, _ := ParseBodyWithOptions([]byte(queryBody), &ParseOptions{Registry: testRegistry, KeepUnknownAdSystems: true})
+	rec.Variables = append(make([]*Variable, 0, 4), rec.Variables...)
+
+	view := rec.FilterAccountType("DIRECT")
+	view.Variables = append(view.Variables, &Variable{Type: "OWNERDOMAIN", Value: "example.com"})
+	rec.Variables = append(rec.Variables, &Variable{Type: "MANAGERDOMAIN", Value: "example.com"})
+
+	if len(view.Variables) != 2 || view.Variables[1].Type != "OWNERDOMAIN" {
+		t.Errorf("Expected filtered view variables not to be changed by the original records")
+	}
+}
+
+// TestRecordsFilterVariableWarnings test filtered views and groups keep the warnings concerning the variables
+func TestRecordsFilterVariableWarnings(t *testing.T) {
+	body := queryBody + "contact=not a contact\nownerdomain=example.com\nownerdomain=example.org\n"
+	rec, _ := ParseBodyWithOptions([]byte(body), &ParseOptions{Registry: testRegistry, KeepUnknownAdSystems: true})
+
+	variables := 0
+	for _, w := range rec.Warnings {
+		if w.Index > 5 {
+			variables++
+		}
+	}
+	if variables != 2 {
+		t.Fatalf("Expected [2] variables warnings but recieved [%d]", variables)
+	}
+
+	view := rec.FilterAdSystem("google.com", testRegistry)
+	for _, code := range []string{CodeInvalidVariableValue, CodeDuplicateVariable} {
+		found := false
+		for _, w := range view.Warnings {
+			found = found || w.Code == code
+		}
+		if !found {
+			t.Errorf("Expected filtered view to keep [%s] variable warning", code)
+		}
+	}
+
+	// groups hold the same warnings as the views filtered by ad system
+	for domain, g := range rec.GroupByAdSystem(testRegistry) {
+		expected := rec.FilterAdSystem(domain, testRegistry)
+		if len(g.DataRecords) != len(expected.DataRecords) || len(g.Warnings) != len(expected.Warnings) {
+			t.Errorf("Expected [%s] group to hold [%d] data records and [%d] warnings but recieved [%d] and [%d]", domain,
+				len(expected.DataRecords), len(expected.Warnings), len(g.DataRecords), len(g.Warnings))
+			continue
+		}
+		for i, w := range g.Warnings {
+			if w != expected.Warnings[i] {
+				t.Errorf("Expected [%s] group warning [%d] to be [%s] but recieved [%s]", domain, i, expected.Warnings[i].Code, w.Code)
+			}
+		}
+	}
+}
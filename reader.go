@@ -0,0 +1,192 @@
+package adstxt
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// DefaultMaxLineLength default maximum length (in bytes) of Ads.txt line. Longer lines are reported with
+// CodeLineTooLong warning and are not parsed (see ParseOptions.MaxLineLength)
+const DefaultMaxLineLength = bufio.MaxScanTokenSize
+
+// Reader read and parse Ads.txt file line by line, without holding the entire file in memory. Reader is used as an
+// iterator: Next advance to the next line of the file, Line and Warnings return the current parsed line and its
+// warnings, and once Next returns false Err return the read error (if any) and Finish return the file level warnings.
+//
+//	rd := adstxt.NewReader(f, nil)
+//	for rd.Next() {
+//		l := rd.Line() // l.DataRecord or l.Variable is set if the line holds an accepted record
+//		for _, w := range rd.Warnings() { ... }
+//	}
+//	if err := rd.Err(); err != nil { ... }
+//	for _, w := range rd.Finish() { ... }
+//
+// Warnings are suppressed by the comment directives declared up to the current line: "adstxt:ignore-file" directive
+// applies only to the lines following it and to the file level warnings. ParseOptions.Strict is ignored by Reader
+type Reader struct {
+	parser   *parser        // parser parse and validate each line read
+	scanner  *bufio.Scanner // scanner split Ads.txt file into lines
+	split    *lineSplitter  // split line split function, keeping track of truncated lines
+	index    int            // index of the current line
+	line     *Line          // line current parsed line
+	warnings []*Warning     // warnings found on the current line, before suppression
+}
+
+// NewReader create new Ads.txt file reader, parsing and validating each line using the specified parse options
+func NewReader(rd io.Reader, opts *ParseOptions) *Reader {
+	split := &lineSplitter{max: opts.maxLineLength()}
+	scanner := bufio.NewScanner(rd)
+	scanner.Split(split.split)
+	if split.max > 0 {
+		// buffer must hold more than max bytes for the split function to detect lines which are too long
+		scanner.Buffer(nil, split.max+2)
+	} else {
+		scanner.Buffer(nil, math.MaxInt32)
+	}
+
+	return &Reader{parser: newParser(opts), scanner: scanner, split: split}
+}
+
+// Next advance the reader to the next line of Ads.txt file, return false once there are no more lines or reading
+// the file failed
+func (r *Reader) Next() bool {
+	if !r.scanner.Scan() {
+		r.line, r.warnings = nil, nil
+		return false
+	}
+
+	r.index++
+	txt := r.scanner.Text()
+	r.parser.suppress.observe(r.index, txt)
+	if r.split.truncated {
+		r.line, r.warnings = r.parser.longLine(r.index, txt, r.split.max)
+	} else {
+		r.line, r.warnings = r.parser.parseLine(r.index, txt)
+	}
+
+	// comments and empty lines are returned as lines without records
+	if r.line == nil {
+		r.line = &Line{Index: r.index, Text: txt, Registry: r.parser.registry, Domain: r.parser.domain}
+	}
+	return true
+}
+
+// Line return the current parsed line. Line DataRecord or Variable is set if the line holds a record which was
+// accepted by the validation rules. Lines longer than the maximum line length are truncated
+func (r *Reader) Line() *Line {
+	return r.line
+}
+
+// Warnings return the warnings found on the current line, which are not suppressed by comment directives
+func (r *Reader) Warnings() []*Warning {
+	reported, _ := r.parser.suppress.apply(r.warnings)
+	return reported
+}
+
+// Err return the error that stopped the reader, nil if the entire Ads.txt file was read
+func (r *Reader) Err() error {
+	return r.scanner.Err()
+}
+
+// Finish return the file level warnings (e.g. duplicate records) which are not suppressed by comment directives.
+// Finish should be called once, after Next returned false
+func (r *Reader) Finish() []*Warning {
+	reported, _ := r.parser.suppress.apply(r.parser.finish())
+	return reported
+}
+
+// ParseReader parse Ads.txt file read from reader based on Ads.txt Specification Version 1.0.1
+// https://iabtechlab.com/wp-content/uploads/2017/09/IABOpenRTB_Ads.txt_Public_Spec_V1-0-1.pdf
+func ParseReader(rd io.Reader) (*Records, error) {
+	return ParseReaderWithOptions(rd, nil)
+}
+
+// ParseReaderWithOptions parse Ads.txt file read from reader, using the specified parse options to control
+// validation rules, strict mode and maximum line length
+func ParseReaderWithOptions(rd io.Reader, opts *ParseOptions) (*Records, error) {
+	records, err := parseRecords(NewReader(rd, opts), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// in strict mode, Ads.txt file with errors is rejected
+	if opts != nil && opts.Strict {
+		if err := validateStrict(records); err != nil {
+			return nil, err
+		}
+	}
+
+	return records, nil
+}
+
+// longLine report Ads.txt line which exceeds the maximum line length. The line is not parsed
+func (p *parser) longLine(index int, txt string, max int) (*Line, []*Warning) {
+	l := &Line{Index: index, Text: txt, Registry: p.registry, Domain: p.domain}
+	msg := fmt.Sprintf("line exceeds the maximum line length of [%d] bytes and was not parsed", max)
+	w := newWarning(CodeLineTooLong, ErrorSevirity, lineField(txt), msg, map[string]string{"maxLength": strconv.Itoa(max)})
+
+	warnings := []*Warning{w}
+	p.review(l, warnings)
+	return l, warnings
+}
+
+// lineSplitter split Ads.txt content into lines, supporting different end-of-line markers (CR, LF, CRLF). Lines
+// longer than the maximum line length are truncated, and the rest of the line is discarded
+type lineSplitter struct {
+	max        int  // max line length, no limit if 0 or less
+	truncated  bool // truncated the last line returned was truncated
+	discarding bool // discarding the rest of the truncated line is being discarded
+}
+
+// split is the bufio.SplitFunc implementation for the lineSplitter type
+func (s *lineSplitter) split(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	end, advance, length := bytes.IndexAny(data, "\r\n"), 0, len(data)
+	switch {
+	// CR at the end of the data might be followed by LF, and is not part of the line length
+	case end == len(data)-1 && data[end] == '\r' && !atEOF:
+		end, length = -1, end
+	case end != -1:
+		advance = end + 1
+		if data[end] == '\r' && len(data) > end+1 && data[end+1] == '\n' {
+			advance++
+		}
+	}
+
+	if end == -1 {
+		switch {
+		// line is too long: return the truncated line and discard the rest of the line
+		case s.max > 0 && length > s.max:
+			if s.discarding {
+				return len(data), nil, nil
+			}
+			s.truncated, s.discarding = true, true
+			return len(data), data[0:s.max], nil
+		// final, non-terminated line
+		case atEOF:
+			end, advance = len(data), len(data)
+		// request more data
+		default:
+			return 0, nil, nil
+		}
+	}
+
+	// end of the truncated line which was already returned
+	if s.discarding {
+		s.discarding = false
+		return advance, nil, nil
+	}
+
+	s.truncated = s.max > 0 && end > s.max
+	if s.truncated {
+		end = s.max
+	}
+	return advance, data[0:end], nil
+}
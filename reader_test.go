@@ -0,0 +1,153 @@
+package adstxt
+
+import (
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// TestReader test iterating over Ads.txt file lines using Reader
+func TestReader(t *testing.T) {
+	body := "# Ads.txt file\n" +
+		"google.com, pub-0000000000001234, DIRECT, f08c47fec0942fa0\n" +
+		"\n" +
+		"google.com, pub-0000000000001234, DIRECTT\n" +
+		"contact=adops@example.com\n" +
+		"google.com, pub-0000000000001234, DIRECT, f08c47fec0942fa0\n"
+
+	rd := NewReader(strings.NewReader(body), &ParseOptions{SuppressErrors: true})
+	lines := []*Line{}
+	warnings := map[int][]*Warning{}
+	for rd.Next() {
+		lines = append(lines, rd.Line())
+		warnings[rd.Line().Index] = rd.Warnings()
+	}
+	if err := rd.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(lines) != 6 {
+		t.Fatalf("Expected reader to return [6] lines but recieved [%d]", len(lines))
+	}
+	for i, l := range lines {
+		if l.Index != i+1 {
+			t.Errorf("Expected line [%d] index to be [%d] but recieved [%d]", i+1, i+1, l.Index)
+		}
+	}
+	if lines[0].DataRecord != nil || lines[0].Variable != nil || lines[0].Text != "# Ads.txt file" {
+		t.Errorf("Expected comment line to be returned without records")
+	}
+	if lines[1].DataRecord == nil || lines[1].DataRecord.PublisherAccountID != "pub-0000000000001234" {
+		t.Errorf("Expected line [2] to hold data record")
+	}
+	if lines[3].DataRecord != nil || len(warnings[4]) != 1 || warnings[4][0].Code != CodeInvalidAccountType {
+		t.Errorf("Expected line [4] data record to be rejected with [%s] warning", CodeInvalidAccountType)
+	}
+	if lines[4].Variable == nil || lines[4].Variable.Value != "adops@example.com" {
+		t.Errorf("Expected line [5] to hold variable")
+	}
+
+	// file level warnings
+	finish := rd.Finish()
+	if len(finish) != 1 || finish[0].Code != CodeDuplicateRecord {
+		t.Errorf("Expected single [%s] file level warning but recieved [%v]", CodeDuplicateRecord, finish)
+	}
+}
+
+// TestReaderSuppress test suppressing warnings by comment directives declared up to the current line
+func TestReaderSuppress(t *testing.T) {
+	body := "google.com, pub-0000000000001234, DIRECTT # adstxt:ignore\n" +
+		"google.com, pub-0000000000001234, DIRECTT\n" +
+		"# adstxt:ignore-file ADSTXT_DUPLICATE_RECORD\n" +
+		"google.com, pub-0000000000001234, DIRECT, f08c47fec0942fa0\n" +
+		"google.com, pub-0000000000001234, DIRECT, f08c47fec0942fa0\n"
+
+	rd := NewReader(strings.NewReader(body), &ParseOptions{SuppressErrors: true})
+	counts := []int{}
+	for rd.Next() {
+		counts = append(counts, len(rd.Warnings()))
+	}
+
+	expected := []int{0, 1, 0, 0, 0}
+	for i := range expected {
+		if counts[i] != expected[i] {
+			t.Errorf("Expected [%d] warnings on line [%d] but recieved [%d]", expected[i], i+1, counts[i])
+		}
+	}
+	if finish := rd.Finish(); len(finish) != 0 {
+		t.Errorf("Expected file level warnings to be suppressed but recieved [%d]", len(finish))
+	}
+}
+
+// TestParseReaderLineEndings test splitting Ads.txt file read byte by byte into lines, using different end-of-line markers
+func TestParseReaderLineEndings(t *testing.T) {
+	bodies := []string{
+		"greenadexchange.com,XF7342,DIRECT\nsubdomain=test.com\n\n",
+		"greenadexchange.com,XF7342,DIRECT\r\nsubdomain=test.com\r\n\r\n",
+		"greenadexchange.com,XF7342,DIRECT\rsubdomain=test.com\r\r",
+		"greenadexchange.com,XF7342,DIRECT\r\nsubdomain=test.com\n\r",
+	}
+
+	for _, b := range bodies {
+		rec, err := ParseReaderWithOptions(iotest.OneByteReader(strings.NewReader(b)), &ParseOptions{Registry: testRegistry})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rec.Body) != 3 || len(rec.DataRecords) != 1 || len(rec.Variables) != 1 {
+			t.Errorf("Expected [%q] to be parsed into 3 lines, single data record and single variable but recieved [%d] lines [%d] data records [%d] variables",
+				b, len(rec.Body), len(rec.DataRecords), len(rec.Variables))
+		}
+	}
+}
+
+// TestParseReaderLongLine test parsing Ads.txt file with line exceeding the maximum line length
+func TestParseReaderLongLine(t *testing.T) {
+	long := "greenadexchange.com,XF7342,RESELLER # " + strings.Repeat("x", 2*DefaultMaxLineLength)
+	body := "greenadexchange.com,XF7342,DIRECT\n" + long + "\r\nsubdomain=test.com"
+
+	// by default, long line is reported and the rest of the file is parsed
+	rec, err := ParseBodyWithOptions([]byte(body), &ParseOptions{Registry: testRegistry})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.DataRecords) != 1 || len(rec.Variables) != 1 || len(rec.Body) != 3 {
+		t.Errorf("Expected long line to be skipped but recieved [%d] data records and [%d] variables", len(rec.DataRecords), len(rec.Variables))
+	}
+	if len(rec.Warnings) != 1 || rec.Warnings[0].Code != CodeLineTooLong || rec.Warnings[0].Index != 2 || rec.Warnings[0].Level != ErrorSevirity {
+		t.Errorf("Expected single [%s] warning on line [2] but recieved [%v]", CodeLineTooLong, rec.Warnings)
+	}
+	if len(rec.Body[1]) != DefaultMaxLineLength {
+		t.Errorf("Expected long line to be truncated to [%d] bytes but recieved [%d]", DefaultMaxLineLength, len(rec.Body[1]))
+	}
+
+	// custom maximum line length
+	rec, _ = ParseBodyWithOptions([]byte(body), &ParseOptions{Registry: testRegistry, MaxLineLength: 20})
+	if len(rec.DataRecords) != 0 || len(rec.Variables) != 1 || len(rec.Warnings) != 2 {
+		t.Errorf("Expected data records to exceed maximum line length of [20] but recieved [%d] data records and [%d] warnings", len(rec.DataRecords), len(rec.Warnings))
+	}
+	if rec.Warnings[0].Params["maxLength"] != "20" {
+		t.Errorf("Expected maximum line length warning parameter to be [20] but recieved [%s]", rec.Warnings[0].Params["maxLength"])
+	}
+
+	// line of exactly the maximum line length is parsed, also when its CRLF end-of-line marker is read in chunks
+	line := "greenadexchange.com,XF7342,DIRECT"
+	for _, b := range []string{line + "\r\nsubdomain=test.com\r\n", line + "\rsubdomain=test.com"} {
+		rec, _ = ParseReaderWithOptions(iotest.OneByteReader(strings.NewReader(b)), &ParseOptions{Registry: testRegistry, MaxLineLength: len(line)})
+		if len(rec.DataRecords) != 1 || len(rec.Variables) != 1 || len(rec.Warnings) != 0 {
+			t.Errorf("Expected [%q] to be parsed with maximum line length of [%d] but recieved [%d] data records and [%v] warnings", b, len(line), len(rec.DataRecords), rec.Warnings)
+		}
+	}
+	rec, _ = ParseReaderWithOptions(iotest.OneByteReader(strings.NewReader(line+"\r\n")), &ParseOptions{Registry: testRegistry, MaxLineLength: len(line) - 1})
+	if len(rec.DataRecords) != 0 || len(rec.Warnings) != 1 || rec.Warnings[0].Code != CodeLineTooLong {
+		t.Errorf("Expected line to exceed maximum line length of [%d] but recieved [%v]", len(line)-1, rec.Warnings)
+	}
+
+	// no line length limit
+	rec, _ = ParseBodyWithOptions([]byte(body), &ParseOptions{Registry: testRegistry, MaxLineLength: -1})
+	if len(rec.DataRecords) != 2 || len(rec.Warnings) != 1 || rec.Warnings[0].Code != CodeConflictingAccountType {
+		t.Errorf("Expected long line to be parsed when line length is not limited but recieved [%d] data records", len(rec.DataRecords))
+	}
+	if len(rec.Body[1]) != len(long) {
+		t.Errorf("Expected long line not to be truncated")
+	}
+}
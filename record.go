@@ -2,8 +2,9 @@ package adstxt
 
 import (
 	"fmt"
-	"regexp"
+	"strconv"
 	"strings"
+	"unicode"
 )
 
 // Ads.txt comment
@@ -12,6 +13,12 @@ const (
 	commentDenote = "#"
 )
 
+// Ads.txt variable
+const (
+	// Variable is declared as <VARIABLE>=<VALUE>
+	variableDenote = "="
+)
+
 // Ads.txt supported account types
 const (
 	// Direct indicates that the Publisher (content owner) directly controls the account
@@ -26,6 +33,10 @@ const (
 	varTypeSubdomain = "subdomain"
 	// Contact information for the owner of the Ads.txt file
 	varTypeContact = "contact"
+	// Business domain of the owner of the Ads.txt file (Ads.txt Specification Version 1.1)
+	varTypeOwnerDomain = "ownerdomain"
+	// Business domain of the primary or exclusive monetization partner (Ads.txt Specification Version 1.1)
+	varTypeManagerDomain = "managerdomain"
 )
 
 // DataRecord hold single Ads.txt data record
@@ -34,108 +45,247 @@ type DataRecord struct {
 	PublisherAccountID string `json:"publisheraccountid"`        // PublisherAccountID the identifier associated with the seller (required)
 	AccountType        string `json:"accountype"`                // AccountType enumeration of the type of account: DIRECT or RESELLER (required)
 	CertAuthorityID    string `json:"certauthorityid,omitempty"` // CertAuthorityID An ID that uniquely identifies the advertising system within a certification authority (optional)
+	Extensions         string `json:"extensions,omitempty"`      // Extensions raw extension data declared after the ";" separator (optional)
+
+	ExtensionFields map[string]string `json:"extensionfields,omitempty"` // ExtensionFields extension data parsed by the ExtensionParser registered for the ad system (optional)
+
+	Line    int    `json:"line,omitempty"`    // Line number (1-based) of the data record in the Ads.txt file
+	Raw     string `json:"raw,omitempty"`     // Raw text of the Ads.txt line the data record was parsed from
+	Comment string `json:"comment,omitempty"` // Comment declared at the end of the data record line (without the "#" character)
 }
 
 // Variable hold single of Ads.txt variable record
 type Variable struct {
-	Type  string `json:"type"`  // Type of variable record. Supported types are subdomain and contact
+	Type  string `json:"type"`  // Type of variable record. Supported types are subdomain, contact, ownerdomain and managerdomain
 	Value string `json:"value"` // Value of variable record
+
+	Line    int    `json:"line,omitempty"`    // Line number (1-based) of the variable in the Ads.txt file
+	Raw     string `json:"raw,omitempty"`     // Raw text of the Ads.txt line the variable was parsed from
+	Comment string `json:"comment,omitempty"` // Comment declared at the end of the variable line (without the "#" character)
+}
+
+// String return Ads.txt data record declaration: <FIELD #1>, <FIELD #2>, <FIELD #3>[, <FIELD #4>][; <EXTENSION>]
+func (r *DataRecord) String() string {
+	fields := []string{r.AdverterDomain, r.PublisherAccountID, r.AccountType}
+	if len(r.CertAuthorityID) > 0 {
+		fields = append(fields, r.CertAuthorityID)
+	}
+
+	str := strings.Join(fields, ", ")
+	if len(r.Extensions) > 0 {
+		str += extensionDenote + " " + r.Extensions
+	}
+	return str
+}
+
+// String return Ads.txt variable declaration: <VARIABLE>=<VALUE>
+func (v *Variable) String() string {
+	return v.Type + "=" + v.Value
+}
+
+// field single field of Ads.txt line, and its position in the line
+type field struct {
+	value     string // value of the field, white spaces trimmed
+	index     int    // index (1-based) of the field in the line
+	column    int    // column (1-based) of the first character of the field value
+	endColumn int    // column (1-based) following the last character of the field value
+}
+
+// splitFields split Ads.txt line into fields separated by sep, keeping track of each field position in the line
+func splitFields(line string, sep string) []field {
+	return splitFieldsN(line, sep, -1)
+}
+
+// splitFieldsN split Ads.txt line into at most n fields separated by sep (no limit if n is negative), the last field
+// holds the rest of the line
+func splitFieldsN(line string, sep string, n int) []field {
+	count := strings.Count(line, sep) + 1
+	if n > 0 && count > n {
+		count = n
+	}
+
+	fields := make([]field, 0, count)
+	offset := 0
+	for index := 1; ; index++ {
+		raw := line[offset:]
+		end := strings.Index(raw, sep)
+		if index == n {
+			end = -1
+		}
+		if end != -1 {
+			raw = raw[0:end]
+		}
+
+		value := strings.TrimSpace(raw)
+		column := offset + len(raw) - len(strings.TrimLeftFunc(raw, unicode.IsSpace)) + 1
+		fields = append(fields, field{value: value, index: index, column: column, endColumn: column + len(value)})
+		if end == -1 {
+			return fields
+		}
+		offset += len(raw) + len(sep)
+	}
 }
 
-// parseDataRecord return new DataRecord parsed from single Ads.txt line
+// shiftFields move fields position by the specified offset, used when fields were parsed from part of Ads.txt line
+func shiftFields(fields []field, offset int) []field {
+	for i := range fields {
+		fields[i].column += offset
+		fields[i].endColumn += offset
+	}
+	return fields
+}
+
+// lineField return field spanning the entire Ads.txt line
+func lineField(line string) field {
+	return field{value: line, column: 1, endColumn: len(line) + 1}
+}
+
+// parseDataRecord return new DataRecord parsed from single Ads.txt line, using the registered extension parsers
 func parseDataRecord(line string) (*DataRecord, *Warning) {
-	// Data record declaraion: <FIELD #1>, <FIELD #2>, <FIELD #3>, <FIELD #4> (optional)
-	fields := strings.Split(line, ",")
+	r, _, w := parseDataRecordFields(line, newExtensionParserSet(defaultRegistry, nil))
+	return r, w
+}
+
+// parseDataRecordFields return new DataRecord parsed from single Ads.txt line, and the position of each of the
+// data record fields in the line. Extension data is parsed using the extension parser of the ad system (if any)
+func parseDataRecordFields(line string, exts *extensionParserSet) (*DataRecord, []field, *Warning) {
+	// Data record declaraion: <FIELD #1>, <FIELD #2>, <FIELD #3>, <FIELD #4> (optional);<EXTENSION> (optional)
+	line, ext := splitExtension(line)
+	fields := splitFields(line, ",")
 
 	filedsLen := len(fields)
 	if filedsLen < 3 || filedsLen > 4 {
-		return nil, &Warning{Level: HighSevirity, Message: fmt.Sprintf("Data record must be declared as <FIELD #1>, <FIELD #2>, <FIELD #3>, <FIELD #4> (optional) pattern")}
+		return nil, fields, newWarning(CodeInvalidFieldCount, ErrorSevirity, lineField(line),
+			fmt.Sprintf("Data record must be declared as <FIELD #1>, <FIELD #2>, <FIELD #3>, <FIELD #4> (optional) pattern"),
+			map[string]string{"fields": strconv.Itoa(filedsLen)})
 	}
 
 	// make sure required fields are not empty
-	adverterDomain := strings.TrimSpace(fields[0])
+	adverterDomain := fields[0].value
 	if len(adverterDomain) == 0 {
-		return nil, &Warning{Level: HighSevirity, Message: fmt.Sprintf("Missing domain name of the advertising system (required)")}
-	}
-
-	if !validateDomainName(adverterDomain) {
-		return nil, &Warning{Level: HighSevirity, Message: fmt.Sprintf("%s is not a valid Ad system domain", adverterDomain)}
+		return nil, fields, newWarning(CodeMissingAdSystemDomain, ErrorSevirity, fields[0],
+			fmt.Sprintf("Missing domain name of the advertising system (required)"), nil)
 	}
 
-	// check that advertiser domain is a valid DNS name
-	err := vaidateAdSystemCName(adverterDomain)
-	if err != nil {
-		return nil, &Warning{Level: LowSevirity, Message: err.Error()}
-	}
-
-	publisherAccountID := strings.TrimSpace(fields[1])
+	publisherAccountID := fields[1].value
 	if len(publisherAccountID) == 0 {
-		return nil, &Warning{Level: HighSevirity, Message: fmt.Sprintf("Missing publisher's Account ID (required)")}
+		return nil, fields, newWarning(CodeMissingAccountID, ErrorSevirity, fields[1],
+			fmt.Sprintf("Missing publisher's Account ID (required)"), nil)
 	}
 
-	accountType := strings.TrimSpace(fields[2])
+	accountType := fields[2].value
 	if len(accountType) == 0 {
-		return nil, &Warning{Level: HighSevirity, Message: fmt.Sprintf("Missing type of account/relationship (required)")}
+		return nil, fields, newWarning(CodeMissingAccountType, ErrorSevirity, fields[2],
+			fmt.Sprintf("Missing type of account/relationship (required)"), nil)
 	}
 
 	// make sure account type is suppoted (case insensitive)
 	if strings.ToUpper(accountType) != accountTypeReseller && strings.ToUpper(accountType) != accountTypeDirect {
-		return nil, &Warning{Level: HighSevirity, Message: fmt.Sprintf("[%s] is not a valid account type. Account type must be [%s] or [%s]",
-			accountType, accountTypeDirect, accountTypeReseller)}
+		return nil, fields, newWarning(CodeInvalidAccountType, ErrorSevirity, fields[2],
+			fmt.Sprintf("[%s] is not a valid account type. Account type must be [%s] or [%s]", accountType, accountTypeDirect, accountTypeReseller),
+			map[string]string{"value": accountType})
 	}
 
 	r := DataRecord{
 		AdverterDomain:     adverterDomain,
 		PublisherAccountID: publisherAccountID,
 		AccountType:        strings.ToUpper(accountType),
+		Extensions:         ext.value,
 	}
 
 	// optional value
 	if filedsLen > 3 {
-		certAuthorityID := strings.TrimSpace(fields[3])
-		r.CertAuthorityID = certAuthorityID
-
-		// check if cert authority id is alphanumeric (if not, it might indicate an error also it is not part of Ads.txt specification)
-		re := regexp.MustCompile("^[a-zA-Z0-9]*$")
-		if !re.MatchString(r.CertAuthorityID) {
-			return &r, &Warning{
-				Level:   LowSevirity,
-				Message: fmt.Sprintf("Certification Authority ID %s may not be correct as it is not alphanumeric", r.CertAuthorityID),
+		r.CertAuthorityID = fields[3].value
+	}
+
+	// parse extension data using the extension parser of the ad system (if any). Extension data which could not be
+	// parsed is reported, while the extension fields which were parsed are kept and the data record is validated as usual
+	var w *Warning
+	if len(r.Extensions) > 0 {
+		if p, ok := exts.lookup(adverterDomain); ok {
+			extFields, err := p.Parse(r.Extensions)
+			if err != nil {
+				w = newWarning(CodeInvalidExtension, WarningSevirity, ext,
+					fmt.Sprintf("Extension data [%s] could not be parsed: %s", r.Extensions, err.Error()),
+					map[string]string{"value": r.Extensions, "error": err.Error()})
+			}
+			if len(extFields) > 0 {
+				r.ExtensionFields = extFields
 			}
 		}
 	}
 
-	return &r, nil
+	return &r, fields, w
 }
 
-// parseVarialbe return new Variable record parsed from Ads.txt line
+// parseVarialbe return new Variable record parsed from Ads.txt line (comment removed)
 func parseVarialbe(line string) (*Variable, *Warning) {
-	// Varaiable declaraion: lines in the a pattern of <VARIABLE>=<VALUE>
-	fields := strings.Split(line, "=")
+	v, _, w := parseVariableFields(line)
+	return v, w
+}
+
+// parseVariableFields return new Variable record parsed from Ads.txt line (comment removed), and the position of
+// the variable type and value in the line
+func parseVariableFields(line string) (*Variable, []field, *Warning) {
+	// Varaiable declaraion: lines in the a pattern of <VARIABLE>=<VALUE>. Value may contain "=" (e.g. URL query)
+	fields := splitFieldsN(line, variableDenote, 2)
 
 	// check that record type is supported, and return new varialbe of that type
-	t := fields[0]
-	switch strings.ToLower(t) {
-	case varTypeSubdomain:
-		return &Variable{
-			Type:  varTypeSubdomain,
-			Value: fields[1],
-		}, nil
-	case varTypeContact:
-		return &Variable{
-			Type:  varTypeContact,
-			Value: fields[1],
-		}, nil
-	default:
-		return nil, &Warning{Level: HighSevirity, Message: fmt.Sprintf("[%s] is not a valid Variable type", t)}
+	t := fields[0].value
+	vt, ok := variableType(t)
+	if !ok {
+		return nil, fields, newWarning(CodeInvalidVariableType, ErrorSevirity, fields[0],
+			fmt.Sprintf("[%s] is not a valid Variable type", t), map[string]string{"type": t})
 	}
+
+	// variable without value is kept as is (as crawlers do), and reported
+	if len(fields) < 2 || len(fields[1].value) == 0 {
+		return &Variable{Type: vt}, fields, newWarning(CodeMissingVariableValue, WarningSevirity, fields[0],
+			fmt.Sprintf("Missing value of [%s] variable", t), map[string]string{"type": vt})
+	}
+
+	return &Variable{Type: vt, Value: fields[1].value}, fields, nil
+}
+
+// variableType return the supported variable type matching t (case insensitive, white spaces trimmed), false if the
+// variable type is not supported
+func variableType(t string) (string, bool) {
+	switch t = strings.ToLower(strings.TrimSpace(t)); t {
+	case varTypeSubdomain, varTypeContact, varTypeOwnerDomain, varTypeManagerDomain:
+		return t, true
+	}
+	return "", false
+}
+
+// isDataRecord return true if Ads.txt line (comment removed) is parsed as data record rather than variable. Only the
+// data record fields are considered, as extension data may hold any number of "=" (e.g. <KEY>=<VALUE> pairs)
+func isDataRecord(line string) bool {
+	fields, _ := splitExtension(line)
+	return strings.Count(fields, ",") >= 2 && strings.Count(fields, "=") <= 5 && !isVariable(line)
+}
+
+// isVariable return true if Ads.txt line (comment removed) declares a supported variable type
+func isVariable(line string) bool {
+	i := strings.Index(line, variableDenote)
+	if i == -1 {
+		return false
+	}
+	_, ok := variableType(line[0:i])
+	return ok
 }
 
 // removeComment removes any comment from Ads.txt line before parsing
 func removeComment(line string) string {
+	line, _ = splitComment(line)
+	return line
+}
+
+// splitComment split Ads.txt line into the line content and the comment text (without the comment denote),
+// white spaces trimmed
+func splitComment(line string) (string, string) {
 	index := strings.Index(line, commentDenote)
-	if index != -1 {
-		line = line[0:index]
+	if index == -1 {
+		return strings.TrimSpace(line), ""
 	}
-	return strings.TrimSpace(line)
+	return strings.TrimSpace(line[0:index]), strings.TrimSpace(line[index+1:])
 }
@@ -127,7 +127,7 @@ func TestParseDataRecordWithComment(t *testing.T) {
 // TestParseDataRecordWithInvalidAdvertisingSystemName test parsing Ads.txt data record line with in valid domain name of the advertising system
 func TestParseDataRecordWithInvalidAdvertisingSystemName(t *testing.T) {
 	line := "greenadexchange,XF7342,DIRECT"
-	_, w := parseDataRecord(line)
+	_, w := validateLine(line)
 	if w == nil {
 		t.Errorf("Expected error when parsing [%s] [%v]", line, w)
 	}
@@ -153,7 +153,7 @@ func TestParseDataRecordWithInvalidAdvertisingSystemName(t *testing.T) {
 // TestParseDataRecordWithInvalidCertName test parsing Ads.txt data record line with in valid dertification authority
 func TestParseDataRecordWithInvalidCertName(t *testing.T) {
 	line := "greenadexchange.com,185,DIRECT,<invalid>"
-	r, w := parseDataRecord(line)
+	r, w := validateLine(line)
 
 	if w == nil {
 		t.Errorf("Expected error when parsing [%s] [%v]", line, w)
@@ -172,6 +172,168 @@ func TestParseDataRecordWithInvalidCertName(t *testing.T) {
 	}
 }
 
+// TestParseDataRecordWithExtension test parsing Ads.txt data record line with extension data after ";"
+func TestParseDataRecordWithExtension(t *testing.T) {
+	line := "greenadexchange.com, XF7342, DIRECT, 5jyxf8k54; extra=1, other"
+
+	r, w := parseDataRecord(line)
+	if w != nil {
+		t.Errorf("Expected no parse warnings when parsing [%s] [%v]", line, w)
+	}
+	if r.CertAuthorityID != "5jyxf8k54" {
+		t.Errorf("Expected Cert Authority ID for [%s] to be [%s] but recieved [%s]", line, "5jyxf8k54", r.CertAuthorityID)
+	}
+	if r.Extensions != "extra=1, other" {
+		t.Errorf("Expected Extensions for [%s] to be [%s] but recieved [%s]", line, "extra=1, other", r.Extensions)
+	}
+
+	// extension data without certification authority ID
+	line = "greenadexchange.com, XF7342, RESELLER;extra=1"
+
+	r, w = parseDataRecord(line)
+	if w != nil {
+		t.Errorf("Expected no parse warnings when parsing [%s] [%v]", line, w)
+	}
+	if r.AccountType != "RESELLER" {
+		t.Errorf("Expected Account Type for [%s] to be [%s] but recieved [%s]", line, "RESELLER", r.AccountType)
+	}
+	if r.CertAuthorityID != "" {
+		t.Errorf("Expected no Cert Authority ID for [%s] but recieved [%s]", line, r.CertAuthorityID)
+	}
+	if r.Extensions != "extra=1" {
+		t.Errorf("Expected Extensions for [%s] to be [%s] but recieved [%s]", line, "extra=1", r.Extensions)
+	}
+
+	j, _ := json.Marshal(r)
+	if string(j) != "{\"adverterdomain\":\"greenadexchange.com\",\"publisheraccountid\":\"XF7342\",\"accountype\":\"RESELLER\",\"extensions\":\"extra=1\"}" {
+		t.Errorf("Json encoded DataRecord is different than expected [%s]", string(j))
+	}
+}
+
+// TestParseDataRecordExtensionParser test parsing extension data using registered ExtensionParser
+func TestParseDataRecordExtensionParser(t *testing.T) {
+	RegisterExtensionParser("GreenAdExchange.com", KeyValueExtensionParser)
+	defer RegisterExtensionParser("greenadexchange.com", nil)
+
+	line := "greenadexchange.com, XF7342, DIRECT, 5jyxf8k54; region=us; format = video"
+
+	r, w := parseDataRecord(line)
+	if w != nil {
+		t.Errorf("Expected no parse warnings when parsing [%s] [%v]", line, w)
+	}
+	if r.ExtensionFields["region"] != "us" || r.ExtensionFields["format"] != "video" {
+		t.Errorf("Expected Extension Fields for [%s] to be parsed but recieved [%v]", line, r.ExtensionFields)
+	}
+
+	// extension data which registered parser fails to parse: record is still valid
+	line = "greenadexchange.com, XF7342, DIRECT, 5jyxf8k54; region"
+
+	r, w = parseDataRecord(line)
+	if w == nil {
+		t.Errorf("Expected parse warning when parsing [%s]", line)
+	}
+	if r == nil || r.Extensions != "region" {
+		t.Errorf("Expected DataRecord with raw Extensions when parsing [%s] [%v]", line, r)
+	}
+
+	// invalid extension data does not stop validating the rest of the data record, and valid pairs are kept
+	line = "greenadexchange.com, XF7342, DIRECT, <cert>; region; format=video; =x"
+
+	l, warnings := newParser(&ParseOptions{Registry: testRegistry}).parseLine(1, line)
+	codes := []string{}
+	for _, w := range warnings {
+		codes = append(codes, w.Code)
+	}
+	if len(codes) != 2 || codes[0] != CodeInvalidExtension || codes[1] != CodeInvalidCertAuthorityID {
+		t.Errorf("Expected [%s] and [%s] warnings when parsing [%s] but recieved %v", CodeInvalidExtension, CodeInvalidCertAuthorityID, line, codes)
+	}
+	if warnings[0].Params["error"] != "[region], [=x] must be declared as <KEY>=<VALUE> pair" {
+		t.Errorf("Expected all invalid extension pairs to be reported but recieved [%s]", warnings[0].Params["error"])
+	}
+	if l.DataRecord == nil || l.DataRecord.ExtensionFields["format"] != "video" || len(l.DataRecord.ExtensionFields) != 1 {
+		t.Errorf("Expected valid extension pairs to be kept when parsing [%s]", line)
+	}
+}
+
+// TestParseDataRecordExtensionParserAdSystem test extension parsers apply to all known domains of the ad system, and
+// may be set by the parse options
+func TestParseDataRecordExtensionParserAdSystem(t *testing.T) {
+	// non canonical domain of the ad system, kept to check its extension data
+	line := "googletagservices.com, pub-0000000000005678, RESELLER, f08c47fec0942fa0; region=us"
+
+	l, _ := newParser(&ParseOptions{Registry: testRegistry, KeepUnknownAdSystems: true}).parseLine(1, line)
+	if l.DataRecord == nil || l.DataRecord.ExtensionFields != nil {
+		t.Errorf("Expected extension data not to be parsed without extension parser when parsing [%s]", line)
+	}
+
+	opts := &ParseOptions{Registry: testRegistry, KeepUnknownAdSystems: true,
+		ExtensionParsers: map[string]ExtensionParser{"Google.com": KeyValueExtensionParser}}
+	l, _ = newParser(opts).parseLine(1, line)
+	if l.DataRecord == nil || l.DataRecord.ExtensionFields["region"] != "us" {
+		t.Errorf("Expected parse options extension parser of [%s] to parse extension data of [%s]", "google.com", line)
+	}
+
+	RegisterExtensionParser("google.com", KeyValueExtensionParser)
+	defer RegisterExtensionParser("google.com", nil)
+
+	l, _ = newParser(&ParseOptions{Registry: testRegistry, KeepUnknownAdSystems: true}).parseLine(1, line)
+	if l.DataRecord == nil || l.DataRecord.ExtensionFields["region"] != "us" {
+		t.Errorf("Expected registered extension parser of [%s] to parse extension data of [%s]", "google.com", line)
+	}
+
+	// parse options extension parser override the registered parser
+	none := ExtensionParserFunc(func(string) (map[string]string, error) { return nil, nil })
+	opts.ExtensionParsers = map[string]ExtensionParser{"googletagservices.com": none}
+	l, _ = newParser(opts).parseLine(1, line)
+	if l.DataRecord == nil || l.DataRecord.ExtensionFields != nil {
+		t.Errorf("Expected parse options extension parser to override the registered parser when parsing [%s]", line)
+	}
+}
+
+// TestParseDataRecordWarningCode test parse warnings code and position of the field the warning concerns
+func TestParseDataRecordWarningCode(t *testing.T) {
+	tests := []struct {
+		line      string
+		code      string
+		field     int
+		column    int
+		endColumn int
+	}{
+		{"greenadexchange.com, XF7342, DIRECT, 5jyxf8k54, x", CodeInvalidFieldCount, 0, 1, 50},
+		{" , XF7342, DIRECT", CodeMissingAdSystemDomain, 1, 2, 2},
+		{"http://greenadexchange.com,XF7342,DIRECT", CodeInvalidAdSystemDomain, 1, 1, 27},
+		{"example.com,XF7342,DIRECT", CodeUnknownAdSystem, 1, 1, 12},
+		{"greenadexchange.com,  ,DIRECT", CodeMissingAccountID, 2, 23, 23},
+		{"greenadexchange.com, XF7342,", CodeMissingAccountType, 3, 29, 29},
+		{"greenadexchange.com, XF7342, unknown", CodeInvalidAccountType, 3, 30, 37},
+		{"greenadexchange.com,185,DIRECT, <invalid>", CodeInvalidCertAuthorityID, 4, 33, 42},
+	}
+
+	for _, test := range tests {
+		_, w := validateLine(test.line)
+		if w == nil {
+			t.Errorf("Expected parse warning when parsing [%s]", test.line)
+			continue
+		}
+		if w.Code != test.code {
+			t.Errorf("Expected warning code for [%s] to be [%s] but recieved [%s]", test.line, test.code, w.Code)
+		}
+		if _, ok := WarningCodes[w.Code]; !ok {
+			t.Errorf("Expected warning code [%s] to be part of warning codes catalogue", w.Code)
+		}
+		if w.Field != test.field || w.Column != test.column || w.EndColumn != test.endColumn {
+			t.Errorf("Expected warning for [%s] to concern field [%d] columns [%d-%d] but recieved field [%d] columns [%d-%d]",
+				test.line, test.field, test.column, test.endColumn, w.Field, w.Column, w.EndColumn)
+		}
+	}
+
+	// structured warning parameters
+	_, w := parseDataRecord("greenadexchange.com, XF7342, unknown")
+	if w.Params["value"] != "unknown" {
+		t.Errorf("Expected warning value parameter to be [unknown] but recieved [%v]", w.Params)
+	}
+}
+
 // TestDataRecordJsonEncode test encoding DataRecord to json
 func TestDataRecordJsonEncode(t *testing.T) {
 	line := "greenadexchange.com, XF7342, DIRECT, 5jyxf8k54"
@@ -276,6 +438,9 @@ func TestParseNotSupportedVarialble(t *testing.T) {
 	if w.Message != "[notSupported] is not a valid Variable type" {
 		t.Errorf("Expected error type for [%s] to be [%s] but recieved [%v]", notSupported, "[notSupported] is not a valid Variable type", w)
 	}
+	if w.Code != CodeInvalidVariableType || w.Params["type"] != "notSupported" {
+		t.Errorf("Expected warning code for [%s] to be [%s] but recieved [%s] %v", notSupported, CodeInvalidVariableType, w.Code, w.Params)
+	}
 }
 
 // TestRemoveComment test creating new line with Ads.txt comment
@@ -293,3 +458,41 @@ func TestRemoveComment(t *testing.T) {
 	}
 
 }
+
+// TestParseVariableLine test parsing variable lines: comment removed, value split on the first "=" only, and
+// white spaces trimmed
+func TestParseVariableLine(t *testing.T) {
+	tests := []struct {
+		line    string
+		varType string
+		value   string
+		comment string
+	}{
+		{"contact=https://example.com/?a=b # ops", varTypeContact, "https://example.com/?a=b", "ops"},
+		{"CONTACT = adops@example.com", varTypeContact, "adops@example.com", ""},
+		{"  subdomain=  dev.example.com  #dev", varTypeSubdomain, "dev.example.com", "dev"},
+		{"managerdomain=manager.com,US", varTypeManagerDomain, "manager.com,US", ""},
+		{"contact=https://example.com/contact?team=ads,ops&region=eu,us", varTypeContact, "https://example.com/contact?team=ads,ops&region=eu,us", ""},
+	}
+
+	for _, test := range tests {
+		rec, err := ParseBodyWithOptions([]byte(test.line), &ParseOptions{Registry: testRegistry})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rec.Variables) != 1 {
+			t.Errorf("Expected [%s] to be parsed into single variable but recieved [%d] [%v]", test.line, len(rec.Variables), rec.Warnings)
+			continue
+		}
+		v := rec.Variables[0]
+		if v.Type != test.varType || v.Value != test.value || v.Comment != test.comment {
+			t.Errorf("Expected [%s] to be parsed into [%s=%s # %s] but recieved [%s # %s]", test.line, test.varType, test.value, test.comment, v, v.Comment)
+		}
+	}
+
+	// missing value
+	v, w := parseVarialbe("contact = ")
+	if v == nil || v.Value != "" || w == nil || w.Code != CodeMissingVariableValue || w.Level != WarningSevirity || w.Params["type"] != varTypeContact {
+		t.Errorf("Expected [%s] warning when parsing variable without value but recieved [%v]", CodeMissingVariableValue, w)
+	}
+}
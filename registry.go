@@ -0,0 +1,460 @@
+package adstxt
+
+import (
+	"bytes"
+	_ "embed" // default ad systems registry data
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Ad systems registry loading error
+const (
+	errRegistryInvalidID         = "Ad system [%s] has invalid ID [%d]"
+	errRegistryDuplicateID       = "Ad system ID [%d] is declared more than once"
+	errRegistryEmptyDomain       = "Empty domain is mapped to ad system ID [%d]"
+	errRegistryDanglingID        = "Domain [%s] is mapped to unknown ad system ID [%d]"
+	errRegistryConflictingDomain = "Domain [%s] is mapped to both ad system ID [%d] and [%d]"
+	errRegistryAccountIDPattern  = "Ad system [%s] has invalid account ID pattern [%s]: %s"
+	errRegistryCSV               = "Failed to parse ad systems registry CSV line [%d]: %s"
+)
+
+// defaultRegistryData known ad systems, based on the IAB Ads.txt normalization mappings (see NormalizationMappingsURL)
+//
+//go:embed data/adsystems.json
+var defaultRegistryData []byte
+
+// defaultRegistry registry used when no registry is specified
+var defaultRegistry = mustLoadRegistry(defaultRegistryData)
+
+// Registry holds all known ad systems (i.e. SSPs/exchanges) and their known domains, used to validate data
+// records field #1. Registry is read-only once loaded, and is safe for concurrent use
+type Registry struct {
+	adSystems map[int]*AdSystem // adSystems known ad systems, by ID
+	domains   map[string]int    // domains known ad system domains (lowercase), mapped to their ad system ID
+	canonical map[string]int    // canonical ad systems canonical domains, mapped to the first ad system (by ID) declaring them
+	ids       []int             // ids sorted ad systems IDs
+}
+
+// registryFile JSON encoding of ad systems registry, following the IAB normalization mappings tables
+type registryFile struct {
+	AdSystems []*AdSystem       `json:"adSystems"`
+	Domains   []*AdSystemDomain `json:"domains"`
+}
+
+// DefaultRegistry return the registry of known ad systems shipped with the package
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// NewRegistry create new registry of the specified ad systems and domains. Domains are normalized (IDNA ASCII form,
+// lowercase, no trailing dot) and certification authority IDs are lowercased, and the
+// registry is validated: ad systems IDs must be unique and positive, and each domain must be mapped to a single
+// known ad system. Account ID patterns must be valid regular expressions, and are matched against the entire account ID
+func NewRegistry(adSystems []*AdSystem, domains []*AdSystemDomain) (*Registry, error) {
+	r := &Registry{adSystems: map[int]*AdSystem{}, domains: map[string]int{}, canonical: map[string]int{}}
+
+	for _, a := range adSystems {
+		if a.ID <= 0 {
+			return nil, fmt.Errorf(errRegistryInvalidID, a.Name, a.ID)
+		}
+		if _, ok := r.adSystems[a.ID]; ok {
+			return nil, fmt.Errorf(errRegistryDuplicateID, a.ID)
+		}
+
+		c := &AdSystem{ID: a.ID, Name: a.Name, CanonicalDomains: []string{}, CertAuthorityIDs: []string{}, Domains: []string{},
+			key: "#" + strconv.Itoa(a.ID)}
+		for _, cName := range a.CanonicalDomains {
+			if cName = normalizeDomain(cName); len(cName) > 0 {
+				c.CanonicalDomains = append(c.CanonicalDomains, cName)
+			}
+		}
+		for _, id := range a.CertAuthorityIDs {
+			if id = strings.ToLower(strings.TrimSpace(id)); len(id) > 0 {
+				c.CertAuthorityIDs = append(c.CertAuthorityIDs, id)
+			}
+		}
+
+		// account ID pattern must match the entire account ID
+		if c.AccountIDPattern = strings.TrimSpace(a.AccountIDPattern); len(c.AccountIDPattern) > 0 {
+			re, err := regexp.Compile("^(?:" + c.AccountIDPattern + ")$")
+			if err != nil {
+				return nil, fmt.Errorf(errRegistryAccountIDPattern, a.Name, a.AccountIDPattern, err.Error())
+			}
+			c.accountID = re
+		}
+		r.adSystems[a.ID] = c
+		r.ids = append(r.ids, a.ID)
+	}
+	sort.Ints(r.ids)
+
+	// index canonical domains, so domains which are only declared as canonical domains are looked up without
+	// scanning all ad systems
+	for _, id := range r.ids {
+		for _, cName := range r.adSystems[id].CanonicalDomains {
+			if _, ok := r.canonical[cName]; !ok {
+				r.canonical[cName] = id
+			}
+		}
+	}
+
+	for _, d := range domains {
+		domain := normalizeDomain(d.Domain)
+		if len(domain) == 0 {
+			return nil, fmt.Errorf(errRegistryEmptyDomain, d.ID)
+		}
+
+		a, ok := r.adSystems[d.ID]
+		if !ok {
+			return nil, fmt.Errorf(errRegistryDanglingID, d.Domain, d.ID)
+		}
+		if id, ok := r.domains[domain]; ok {
+			if id != d.ID {
+				return nil, fmt.Errorf(errRegistryConflictingDomain, d.Domain, id, d.ID)
+			}
+			continue
+		}
+
+		r.domains[domain] = d.ID
+		a.Domains = append(a.Domains, domain)
+	}
+
+	return r, nil
+}
+
+// LoadRegistry load registry of known ad systems from JSON encoded reader (see data/adsystems.json)
+func LoadRegistry(rd io.Reader) (*Registry, error) {
+	f := &registryFile{}
+	if err := json.NewDecoder(rd).Decode(f); err != nil {
+		return nil, err
+	}
+	return NewRegistry(f.AdSystems, f.Domains)
+}
+
+// LoadRegistryFile load registry of known ad systems from JSON file
+func LoadRegistryFile(path string) (*Registry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return LoadRegistry(f)
+}
+
+// LoadRegistryCSV load registry of known ad systems from CSV encoded readers, following the IAB normalization mappings
+// tables: ad systems lines are declared as <ID>,<NAME>,<CANONICAL_DOMAIN> (multiple canonical domains are separated
+// by comma) and domains lines are declared as <DOMAIN>,<ID>. Header line is optional
+func LoadRegistryCSV(adSystems io.Reader, domains io.Reader) (*Registry, error) {
+	as := []*AdSystem{}
+	err := readRegistryCSV(adSystems, 2, func(rec []string) error {
+		id, err := strconv.Atoi(strings.TrimSpace(rec[0]))
+		if err != nil {
+			return err
+		}
+
+		a := &AdSystem{ID: id, Name: strings.TrimSpace(rec[1])}
+		if len(rec) > 2 && !strings.EqualFold(strings.TrimSpace(rec[2]), "NULL") {
+			a.CanonicalDomains = strings.Split(strings.Join(rec[2:], ","), ",")
+		}
+		as = append(as, a)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ds := []*AdSystemDomain{}
+	err = readRegistryCSV(domains, 2, func(rec []string) error {
+		id, err := strconv.Atoi(strings.TrimSpace(rec[1]))
+		if err != nil {
+			return err
+		}
+
+		ds = append(ds, &AdSystemDomain{Domain: rec[0], ID: id})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRegistry(as, ds)
+}
+
+// LoadRegistryCSVFiles load registry of known ad systems from CSV files (see LoadRegistryCSV)
+func LoadRegistryCSVFiles(adSystemsPath string, domainsPath string) (*Registry, error) {
+	as, err := os.Open(adSystemsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer as.Close()
+
+	ds, err := os.Open(domainsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer ds.Close()
+
+	return LoadRegistryCSV(as, ds)
+}
+
+// readRegistryCSV read CSV lines with at least minFields fields, skipping the header line (if any)
+func readRegistryCSV(rd io.Reader, minFields int, line func(rec []string) error) error {
+	cr := csv.NewReader(rd)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return err
+	}
+
+	for i, rec := range records {
+		if len(rec) < minFields {
+			return fmt.Errorf(errRegistryCSV, i+1, fmt.Sprintf("expected at least [%d] fields", minFields))
+		}
+		if err := line(rec); err != nil {
+			// first line may be a header line
+			if i == 0 {
+				continue
+			}
+			return fmt.Errorf(errRegistryCSV, i+1, err.Error())
+		}
+	}
+	return nil
+}
+
+// mustLoadRegistry load registry from JSON encoded data, and panic if the data is not valid
+func mustLoadRegistry(b []byte) *Registry {
+	r, err := LoadRegistry(strings.NewReader(string(b)))
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// AdSystems return all known ad systems, sorted by ID
+func (r *Registry) AdSystems() []*AdSystem {
+	adSystems := make([]*AdSystem, 0, len(r.ids))
+	for _, id := range r.ids {
+		adSystems = append(adSystems, r.adSystems[id])
+	}
+	return adSystems
+}
+
+// WriteJSON write registry to w, encoded as JSON (see LoadRegistry). Ad systems are written by ID, followed by
+// their domains, one entry per line so changes to the registry file are easy to review
+func (r *Registry) WriteJSON(w io.Writer) error {
+	adSystems := []string{}
+	domains := []string{}
+	for _, a := range r.AdSystems() {
+		entry := fmt.Sprintf(`    {"id": %d, "name": %s`, a.ID, jsonString(a.Name))
+		if len(a.CanonicalDomains) > 0 {
+			cNames := []string{}
+			for _, cName := range a.CanonicalDomains {
+				cNames = append(cNames, jsonString(cName))
+			}
+			entry += fmt.Sprintf(`, "canonicalDomains": [%s]`, strings.Join(cNames, ", "))
+		}
+		if len(a.CertAuthorityIDs) > 0 {
+			ids := []string{}
+			for _, id := range a.CertAuthorityIDs {
+				ids = append(ids, jsonString(id))
+			}
+			entry += fmt.Sprintf(`, "certAuthorityIds": [%s]`, strings.Join(ids, ", "))
+		}
+		if len(a.AccountIDPattern) > 0 {
+			entry += fmt.Sprintf(`, "accountIdPattern": %s`, jsonString(a.AccountIDPattern))
+		}
+		adSystems = append(adSystems, entry+"}")
+
+		sorted := append([]string{}, a.Domains...)
+		sort.Strings(sorted)
+		for _, d := range sorted {
+			domains = append(domains, fmt.Sprintf(`    {"domain": %s, "id": %d}`, jsonString(d), a.ID))
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "{\n  \"adSystems\": [\n%s\n  ],\n  \"domains\": [\n%s\n  ]\n}\n",
+		strings.Join(adSystems, ",\n"), strings.Join(domains, ",\n"))
+	return err
+}
+
+// jsonString encode string as JSON, without escaping HTML characters (found in some known domains)
+func jsonString(s string) string {
+	var b bytes.Buffer
+	e := json.NewEncoder(&b)
+	e.SetEscapeHTML(false)
+	e.Encode(s)
+	return strings.TrimSpace(b.String())
+}
+
+// Lookup return the ad system of the specified domain (in any of its forms, e.g. uppercase or Unicode): domains mapped to an ad system first, and
+// then the ad systems canonical domains
+func (r *Registry) Lookup(domain string) (*AdSystem, bool) {
+	domain = normalizeDomain(domain)
+	if id, ok := r.domains[domain]; ok {
+		return r.adSystems[id], true
+	}
+	if id, ok := r.canonical[domain]; ok {
+		return r.adSystems[id], true
+	}
+	return nil, false
+}
+
+// LookupID return the ad system of the specified ID
+func (r *Registry) LookupID(id int) (*AdSystem, bool) {
+	a, ok := r.adSystems[id]
+	return a, ok
+}
+
+// Search return the ad systems which name matches the specified query (case insensitive, ignoring white spaces and
+// punctuation). Exact matches are returned first, followed by prefix matches, partial matches and finally ad systems
+// which name includes all query characters in order (e.g. "rbcn" matches "Rubicon Project")
+func (r *Registry) Search(query string) []*AdSystem {
+	q := searchKey(query)
+	if len(q) == 0 {
+		return []*AdSystem{}
+	}
+
+	type match struct {
+		a     *AdSystem
+		score int
+	}
+	matches := []match{}
+	for _, a := range r.AdSystems() {
+		name := searchKey(a.Name)
+		switch {
+		case name == q:
+			matches = append(matches, match{a, 0})
+		case strings.HasPrefix(name, q):
+			matches = append(matches, match{a, 1})
+		case strings.Contains(name, q):
+			matches = append(matches, match{a, 2})
+		case isSubsequence(q, name):
+			matches = append(matches, match{a, 3})
+		}
+	}
+
+	// ad systems are already sorted by ID, keep that order for matches of the same score
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score < matches[j].score })
+	adSystems := make([]*AdSystem, len(matches))
+	for i, m := range matches {
+		adSystems[i] = m.a
+	}
+	return adSystems
+}
+
+// Aliases return all known domains of the ad system of the specified domain, other than its canonical domains
+func (r *Registry) Aliases(domain string) []string {
+	if a, ok := r.Lookup(domain); ok {
+		return a.Aliases()
+	}
+	return []string{}
+}
+
+// Canonicalize return the canonical domain of the ad system of the specified domain. If the domain is unknown, or its ad
+// system declares no canonical domain (or multiple canonical domains, none of them is the specified domain), the
+// lowercase domain is returned and ok is false
+func (r *Registry) Canonicalize(domain string) (canonical string, ok bool) {
+	lcDomain := normalizeDomain(domain)
+	a, found := r.Lookup(lcDomain)
+	if !found {
+		return lcDomain, false
+	}
+
+	switch {
+	case a.isCanonical(lcDomain):
+		return lcDomain, true
+	case len(a.CanonicalDomains) == 1:
+		return a.CanonicalDomains[0], true
+	default:
+		return lcDomain, false
+	}
+}
+
+// Lookup return the ad system of the specified domain, using the default registry
+func Lookup(domain string) (*AdSystem, bool) {
+	return defaultRegistry.Lookup(domain)
+}
+
+// LookupID return the ad system of the specified ID, using the default registry
+func LookupID(id int) (*AdSystem, bool) {
+	return defaultRegistry.LookupID(id)
+}
+
+// Search return the ad systems which name matches the specified query, using the default registry
+func Search(query string) []*AdSystem {
+	return defaultRegistry.Search(query)
+}
+
+// Aliases return all known domains of the ad system of the specified domain other than its canonical domains, using
+// the default registry
+func Aliases(domain string) []string {
+	return defaultRegistry.Aliases(domain)
+}
+
+// Canonicalize return the canonical domain of the ad system of the specified domain, using the default registry
+func Canonicalize(domain string) (string, bool) {
+	return defaultRegistry.Canonicalize(domain)
+}
+
+// searchKey normalize ad system name for search: lowercase letters and digits only
+func searchKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// isSubsequence check if all characters of sub appear in s, in order
+func isSubsequence(sub string, s string) bool {
+	for _, c := range sub {
+		i := strings.IndexRune(s, c)
+		if i == -1 {
+			return false
+		}
+		s = s[i+len(string(c)):]
+	}
+	return true
+}
+
+// validate that the specified ad system domain is a known ad system, and that it is the ad system canonical
+// domain. It does not imply that any of the ad systems have been vetted or certified
+func (r *Registry) validate(domain string) error {
+	a, ok := r.Lookup(domain)
+	if !ok {
+		return &adSystemError{code: CodeUnknownAdSystem, msg: fmt.Sprintf("Please verify that %s is a known exchange domain", domain)}
+	}
+
+	// domain does not match Ad System Canonical name: it is still valid but publisher should probably use canonical name
+	if len(a.CanonicalDomains) > 0 && !a.isCanonical(domain) {
+		canonical := strings.Join(a.CanonicalDomains, ", ")
+		return &adSystemError{
+			code:      CodeNonCanonicalAdSystem,
+			canonical: canonical,
+			msg: fmt.Sprintf("%s is not the preferred form of the exchange domain. Please consider using %s as the canonical domain name",
+				domain, canonical),
+		}
+	}
+
+	return nil
+}
+
+// key return key identifying the ad system of the specified domain: all known domains of an ad system share
+// the same key, while unknown domains are identified by their lowercase form
+func (r *Registry) key(domain string) string {
+	if a, ok := r.Lookup(domain); ok {
+		return a.key
+	}
+	return normalizeDomain(domain)
+}
@@ -0,0 +1,186 @@
+package adstxt
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestDefaultRegistry test the registry of known ad systems shipped with the package
+func TestDefaultRegistry(t *testing.T) {
+	r := DefaultRegistry()
+	if len(r.AdSystems()) == 0 {
+		t.Fatal("Expected default registry to hold known ad systems")
+	}
+
+	// domains declared with uppercase characters are matched case insensitive
+	for _, d := range []string{"chocolateplatform.com", "Chocolateplatform.com", "rtk.io", "google.com", "ADTECH.COM"} {
+		if _, ok := r.Lookup(d); !ok {
+			t.Errorf("Expected [%s] to be a known ad system domain", d)
+		}
+	}
+
+	// ad systems are sorted by ID
+	adSystems := r.AdSystems()
+	for i := 1; i < len(adSystems); i++ {
+		if adSystems[i-1].ID >= adSystems[i].ID {
+			t.Errorf("Expected ad systems to be sorted by ID but [%d] is followed by [%d]", adSystems[i-1].ID, adSystems[i].ID)
+		}
+	}
+}
+
+// TestLoadRegistry test loading registry of known ad systems from JSON
+func TestLoadRegistry(t *testing.T) {
+	data := `{
+		"adSystems": [{"id": 1, "name": "Acme", "canonicalDomains": ["Acme.com"]}, {"id": 2, "name": "Other"}],
+		"domains": [{"domain": "ACME.net", "id": 1}, {"domain": "acme.net", "id": 1}, {"domain": "other.com", "id": 2}]
+	}`
+
+	r, err := LoadRegistry(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Failed to load registry: %s", err)
+	}
+
+	if err := r.validate("acme.com"); err != nil {
+		t.Errorf("Expected [acme.com] to be canonical domain but recieved [%s]", err)
+	}
+	if e, ok := r.validate("acme.net").(*adSystemError); !ok || e.code != CodeNonCanonicalAdSystem || e.canonical != "acme.com" {
+		t.Errorf("Expected [acme.net] to be non canonical domain of [acme.com] but recieved [%v]", e)
+	}
+	if err := r.validate("other.com"); err != nil {
+		t.Errorf("Expected [other.com] to be valid domain but recieved [%s]", err)
+	}
+	if e, ok := r.validate("example.com").(*adSystemError); !ok || e.code != CodeUnknownAdSystem {
+		t.Errorf("Expected [example.com] to be unknown ad system but recieved [%v]", e)
+	}
+	if a := r.AdSystems()[0]; len(a.Domains) != 1 || a.Domains[0] != "acme.net" {
+		t.Errorf("Expected [acme.net] to be the only domain of [Acme] but recieved [%v]", a.Domains)
+	}
+}
+
+// TestLoadRegistryInvalid test registry validation on load
+func TestLoadRegistryInvalid(t *testing.T) {
+	tests := []string{
+		`{"adSystems": [{"id": 1, "name": "Acme"}], "domains": [{"domain": "acme.com", "id": 2}]}`,
+		`{"adSystems": [{"id": 1, "name": "Acme"}, {"id": 2, "name": "Other"}], "domains": [{"domain": "acme.com", "id": 1}, {"domain": "ACME.com", "id": 2}]}`,
+		`{"adSystems": [{"id": 1, "name": "Acme"}, {"id": 1, "name": "Other"}]}`,
+		`{"adSystems": [{"id": 0, "name": "Acme"}]}`,
+		`{"adSystems": [{"id": 1, "name": "Acme"}], "domains": [{"domain": " ", "id": 1}]}`,
+		`{"adSystems": [`,
+	}
+
+	for _, data := range tests {
+		if _, err := LoadRegistry(strings.NewReader(data)); err == nil {
+			t.Errorf("Expected error when loading invalid registry [%s]", data)
+		}
+	}
+}
+
+// TestLoadRegistryCSV test loading registry of known ad systems from CSV
+func TestLoadRegistryCSV(t *testing.T) {
+	adSystems := "ID,NAME,CANONICAL_DOMAIN\n1,Acme,\"acme.com, acme.net\"\n2,Other,NULL\n"
+	domains := "DOMAIN,ID\nacme.io,1\nOther.com,2\n"
+
+	r, err := LoadRegistryCSV(strings.NewReader(adSystems), strings.NewReader(domains))
+	if err != nil {
+		t.Fatalf("Failed to load registry: %s", err)
+	}
+	if a := r.AdSystems()[0]; len(a.CanonicalDomains) != 2 || a.CanonicalDomains[1] != "acme.net" {
+		t.Errorf("Expected [Acme] canonical domains to be [acme.com acme.net] but recieved [%v]", a.CanonicalDomains)
+	}
+	if err := r.validate("other.com"); err != nil {
+		t.Errorf("Expected [other.com] to be valid domain but recieved [%s]", err)
+	}
+
+	if _, err := LoadRegistryCSV(strings.NewReader(adSystems), strings.NewReader("acme.io,1\nother.com,x\n")); err == nil {
+		t.Error("Expected error when loading registry with invalid ad system ID")
+	}
+}
+
+// TestParseOptionsRegistry test validating Ads.txt file using custom registry of known ad systems
+func TestParseOptionsRegistry(t *testing.T) {
+	r, _ := NewRegistry([]*AdSystem{{ID: 1, Name: "Acme", CanonicalDomains: []string{"acme.com"}}}, nil)
+	b := []byte("acme.com, 1234, DIRECT\ngoogle.com, pub-1234, DIRECT")
+
+	res, _ := ParseBodyWithOptions(b, &ParseOptions{Registry: r})
+	if len(res.Warnings) != 1 || res.Warnings[0].Code != CodeUnknownAdSystem || res.Warnings[0].Index != 2 {
+		t.Errorf("Expected [google.com] to be unknown ad system but recieved [%v]", res.Warnings)
+	}
+}
+
+// TestRegistryLookup test looking up ad systems by domain and ID
+func TestRegistryLookup(t *testing.T) {
+	a, ok := Lookup("GoogleTagServices.com")
+	if !ok || a.ID != 8 || a.Name != "Google" || a.CanonicalDomains[0] != "google.com" {
+		t.Fatalf("Expected [googletagservices.com] to be Google domain but recieved [%v]", a)
+	}
+
+	if b, ok := LookupID(8); !ok || b != a {
+		t.Errorf("Expected ad system [8] to be Google but recieved [%v]", b)
+	}
+	if _, ok := LookupID(-1); ok {
+		t.Error("Expected ad system [-1] not to be found")
+	}
+	if _, ok := Lookup("example.com"); ok {
+		t.Error("Expected [example.com] not to be a known ad system domain")
+	}
+
+	aliases := Aliases("google.com")
+	if len(aliases) == 0 || containsFold(aliases, "google.com") || !containsFold(aliases, "googletagservices.com") {
+		t.Errorf("Expected Google aliases to include [googletagservices.com] but not [google.com], recieved [%v]", aliases)
+	}
+}
+
+// TestRegistrySearch test searching ad systems by name
+func TestRegistrySearch(t *testing.T) {
+	r, _ := NewRegistry([]*AdSystem{
+		{ID: 1, Name: "Rubicon Project"},
+		{ID: 2, Name: "Project Rubicon"},
+		{ID: 3, Name: "Rubicon"},
+		{ID: 4, Name: "Google"},
+	}, nil)
+
+	tests := map[string][]int{
+		"rubicon":  {3, 1, 2},
+		"Rubicon-": {3, 1, 2},
+		"rbcn":     {1, 2, 3},
+		"project":  {2, 1},
+		"GOOGLE":   {4},
+		"amazon":   {},
+		"  ":       {},
+	}
+
+	for query, ids := range tests {
+		res := r.Search(query)
+		if len(res) != len(ids) {
+			t.Errorf("Expected [%d] ad systems to match [%s] but recieved [%d]", len(ids), query, len(res))
+			continue
+		}
+		for i, id := range ids {
+			if res[i].ID != id {
+				t.Errorf("Expected match [%d] of [%s] to be ad system [%d] but recieved [%d]", i, query, id, res[i].ID)
+			}
+		}
+	}
+}
+
+// TestRegistryCanonicalize test canonicalizing ad systems domains
+func TestRegistryCanonicalize(t *testing.T) {
+	tests := []struct {
+		domain    string
+		canonical string
+		ok        bool
+	}{
+		{"google.com", "google.com", true},
+		{"GoogleTagServices.com ", "google.com", true},
+		{"rubicon.com", "rubiconproject.com", true},
+		{"aolcloud.net", "aolcloud.net", true},
+		{"Example.com", "example.com", false},
+	}
+
+	for _, test := range tests {
+		canonical, ok := Canonicalize(test.domain)
+		if canonical != test.canonical || ok != test.ok {
+			t.Errorf("Expected [%s] to be canonicalized to [%s] (%t) but recieved [%s] (%t)", test.domain, test.canonical, test.ok, canonical, ok)
+		}
+	}
+}
@@ -12,18 +12,30 @@ type Request struct {
 	URL    string `json:"url"`    // URL of the Ads.txt file to fetch
 }
 
-// NewRequest create new Ads.txt file request from remote host
+// NewRequest create new Ads.txt file request from remote host, resolving its root domain using the default domain resolver
 func NewRequest(rawurl string) (*Request, error) {
+	return defaultResolver.NewRequest(rawurl)
+}
+
+// NewRequest create new Ads.txt file request from remote host, resolving its root domain using the domain resolver
+func (r *DomainResolver) NewRequest(rawurl string) (*Request, error) {
+	// add scheme to Ads.txt URL if it's missing (by default we will add http and not https since it seems more common. If the site is
+	// running using HTTPS, we will usually get an HTTP redirect response and will handle it). Scheme is added before parsing
+	// the URL, otherwise host with port (e.g. "example.com:8080") is parsed as URL scheme
+	if !hasScheme(rawurl) {
+		rawurl = "http://" + strings.TrimPrefix(rawurl, "//")
+	}
+
 	u, err := url.Parse(rawurl)
 	if err != nil {
 		return nil, err
 	}
 
-	// add scheme to Ads.txt URL if it's missing (by default we will add http and not https since it seems more common. If the site is
-	// running using HTTPS, we will usually get an HTTP redirect response and will handle it)
-	if u.Scheme == "" {
-		u.Scheme = "http"
-	}
+	// normalize remote host name (IDNA ASCII form, lowercase, no trailing dot)
+	u.Host = normalizeHost(u.Host)
+
+	// Ads.txt file is requested without the URL query and fragment
+	u.RawQuery, u.Fragment, u.RawFragment, u.ForceQuery = "", "", "", false
 
 	// add "/ads.txt" to URL path
 	if !strings.HasSuffix(u.Path, "/ads.txt") {
@@ -32,7 +44,7 @@ func NewRequest(rawurl string) (*Request, error) {
 
 	// Publishers should post the "/ads.txt" file on their root domain and any subdomains as needed.
 	// Root domain is defined as the “public suffix” plus one string in the name
-	d, err := rootDomain(rawurl)
+	d, err := r.RootDomain(rawurl)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package adstxt
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -17,7 +18,17 @@ func TestNewRequest(t *testing.T) {
 		"example.com/path/":              Request{URL: "http://example.com/path/ads.txt", Domain: "example.com"},
 		"sub-domain.test.com":            Request{URL: "http://sub-domain.test.com/ads.txt", Domain: "test.com"},
 		"http://sub.domain.test.com":     Request{URL: "http://sub.domain.test.com/ads.txt", Domain: "test.com"},
-		"http://abc.raisingourkids.com/": Request{URL: "http://abc.raisingourkids.com/ads.txt", Domain: "raisingourkids.com"}}
+		"http://abc.raisingourkids.com/": Request{URL: "http://abc.raisingourkids.com/ads.txt", Domain: "raisingourkids.com"},
+		"WWW.Example.COM":                Request{URL: "http://www.example.com/ads.txt", Domain: "example.com"},
+		"https://www.example.com.:8080/": Request{URL: "https://www.example.com:8080/ads.txt", Domain: "example.com"},
+		"bücher.de":                      Request{URL: "http://xn--bcher-kva.de/ads.txt", Domain: "xn--bcher-kva.de"},
+		"https://www.Bücher.de/":         Request{URL: "https://www.xn--bcher-kva.de/ads.txt", Domain: "xn--bcher-kva.de"},
+		"http://xn--bcher-kva.de":        Request{URL: "http://xn--bcher-kva.de/ads.txt", Domain: "xn--bcher-kva.de"},
+		"https://example.com/?utm=1#top": Request{URL: "https://example.com/ads.txt", Domain: "example.com"},
+		"example.com/path?":              Request{URL: "http://example.com/path/ads.txt", Domain: "example.com"},
+		"www.example.com:8080":           Request{URL: "http://www.example.com:8080/ads.txt", Domain: "example.com"},
+		"//www.example.com/":             Request{URL: "http://www.example.com/ads.txt", Domain: "example.com"},
+		"example.com/?to=https://x.com":  Request{URL: "http://example.com/ads.txt", Domain: "example.com"}}
 
 	for k, v := range domains {
 		r, _ := NewRequest(k)
@@ -29,3 +40,29 @@ func TestNewRequest(t *testing.T) {
 		}
 	}
 }
+
+// FuzzNewRequest fuzz creating Ads.txt request: request URL is the Ads.txt file of the remote host, and request
+// domain is the root domain of the request URL
+func FuzzNewRequest(f *testing.F) {
+	for _, seed := range []string{"example.com", "https://www.example.com/", "http://port.com:8080/grid", "bücher.de",
+		"WWW.Example.COM.", "http://[::1]:8080/", "127.0.0.1", "co.uk", "user@example.com/path?q=1#ads", ""} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, rawurl string) {
+		req, err := NewRequest(rawurl)
+		if err != nil {
+			return
+		}
+
+		if !strings.HasSuffix(req.URL, "/ads.txt") {
+			t.Errorf("Expected request URL [%s] of [%q] to be Ads.txt file URL", req.URL, rawurl)
+		}
+		if len(req.Domain) == 0 {
+			t.Errorf("Expected request of [%q] to have root domain", rawurl)
+		}
+		if d, err := rootDomain(req.URL); err != nil || d != req.Domain {
+			t.Errorf("Expected root domain of request URL [%s] to be [%s] but recieved [%s] [%v]", req.URL, req.Domain, d, err)
+		}
+	})
+}
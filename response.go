@@ -13,7 +13,8 @@ type Records struct {
 	DataRecords []*DataRecord `json:"dataRecords"`
 	Variables   []*Variable   `json:"variables"`
 	Warnings    []*Warning    `json:"warnings"`
-	Body        []string      `json:"body"` // Original Ads.txt file content
+	Suppressed  []*Warning    `json:"suppressed,omitempty"` // Warnings suppressed by comment directives (only if requested by ParseOptions)
+	Body        []string      `json:"body"`                 // Original Ads.txt file content (empty if discarded by ParseOptions)
 }
 
 // Response to an Ads.txt request: collection of Data\Variable records parsed from Ads.txt file and
@@ -21,59 +22,204 @@ type Records struct {
 type Response struct {
 	*Request
 	*Records
-	Expires time.Time `json:"expires"` // Ads.txt file expiration date
+	Expires       time.Time          `json:"expires"`       // Ads.txt file expiration date
+	Authorization AuthorizationState `json:"authorization"` // Authorization state of the Ads.txt file (no file, empty, placeholder only or has sellers)
 }
 
-// parseRecords parse Ads.txt file content
-func parseRecords(lines []string) *Records {
-	r := &Records{
+// parser parse Ads.txt lines into Data\Variable records, validating each parsed line using the enabled rules
+type parser struct {
+	opts     *ParseOptions // opts Ads.txt parse options
+	registry *Registry     // registry of known ad systems used to validate data records
+	domain   string        // domain root domain of the Ads.txt file, used to validate subdomain variables
+	rules    []Rule        // rules validation rules enabled by the parse options
+	checkers []FileChecker // checkers file level validation rules enabled by the parse options
+	suppress *suppressions // suppress warning suppression directives declared in Ads.txt file comments
+
+	extensions *extensionParserSet // extensions extension parsers of the ad systems, registered or set by the parse options
+}
+
+// newParser create new Ads.txt parser using the specified parse options
+func newParser(opts *ParseOptions) *parser {
+	return &parser{opts: opts, registry: opts.registry(), domain: opts.domain(), rules: opts.rules(), checkers: opts.fileCheckers(), suppress: newSuppressions(opts.suppressErrors()),
+		extensions: opts.extensionParsers()}
+}
+
+// newRecords create new empty collection of Ads.txt records
+func newRecords() *Records {
+	return &Records{
 		DataRecords: []*DataRecord{},
 		Variables:   []*Variable{},
 		Warnings:    []*Warning{},
-		Body:        lines,
+		Body:        []string{},
+	}
+}
+
+// parseRecords parse Ads.txt file content read line by line, collecting all records and warnings
+func parseRecords(rd *Reader, opts *ParseOptions) (*Records, error) {
+	r := newRecords()
+
+	// loop over Ads.txt file lines and collect each line record
+	keepBody := opts == nil || !opts.DiscardBody
+	for rd.Next() {
+		if keepBody {
+			r.Body = append(r.Body, rd.line.Text)
+		}
+		r.addLine(rd.line, rd.warnings)
+	}
+	if err := rd.Err(); err != nil {
+		return nil, err
 	}
 
-	// loop over Ads.txt file lines and parse each line into Ads.txt record
-	for index, l := range lines {
-		r.parseRecord(index+1, l)
+	// validate Ads.txt file as a whole
+	r.Warnings = append(r.Warnings, rd.parser.finish()...)
+
+	// remove warnings suppressed by comment directives (file level directives may be declared anywhere in the file)
+	var suppressed []*Warning
+	r.Warnings, suppressed = rd.parser.suppress.apply(r.Warnings)
+	if opts != nil && opts.IncludeSuppressed {
+		r.Suppressed = suppressed
 	}
 
-	return r
+	return r, nil
 }
 
-// parseRecord parse a single Ads.txt line into Data\Variable record
-func (r *Records) parseRecord(index int, txt string) {
-	line := removeComment(txt)
+// addLine add the records and warnings of single parsed Ads.txt line
+func (r *Records) addLine(l *Line, warnings []*Warning) {
+	r.Warnings = append(r.Warnings, warnings...)
+	if l.DataRecord != nil {
+		r.DataRecords = append(r.DataRecords, l.DataRecord)
+	}
+	if l.Variable != nil {
+		r.Variables = append(r.Variables, l.Variable)
+	}
+}
+
+// parseLine parse a single Ads.txt line into Data\Variable record and validate it. Line is nil for comments and
+// empty lines; line record is nil if the line could not be parsed or was rejected by validation rules
+func (p *parser) parseLine(index int, txt string) (*Line, []*Warning) {
+	line, comment := splitComment(txt)
 
 	// ignore comments and empty line
 	if len(line) == 0 || string(line) == commentDenote {
-		return
+		return nil, nil
+	}
+
+	// fields position is relative to the line with comment and white spaces removed
+	offset := strings.Index(txt, line)
+	l := &Line{Index: index, Text: txt, Registry: p.registry, Domain: p.domain}
+	warnings := []*Warning{}
+
+	// in tolerant mode, common format mistakes of data records are repaired before the line is parsed, and the fields
+	// of the repaired line are mapped back to their position in the original line
+	original, stripped := []field(nil), line
+	if p.opts != nil && p.opts.Tolerant {
+		var repaired []*Warning
+		line, original, repaired = repairDataRecord(line)
+		for _, w := range repaired {
+			w.shift(offset)
+			warnings = append(warnings, w)
+		}
 	}
 
 	// parse line into Data\Variable record
-	if strings.Count(line, ",") >= 2 && strings.Count(line, "=") <= 5 {
-		dr, w := parseDataRecord(line)
-		if w != nil {
-			w.Index = index
-			w.Text = txt
-			r.Warnings = append(r.Warnings, w)
+	if isDataRecord(line) {
+		dr, fields, w := parseDataRecordFields(line, p.extensions)
+		if original != nil {
+			fields = repairedFields(fields, original, stripped)
+			if w != nil {
+				w.Column, w.EndColumn = repairedColumns(w.Field, original, stripped)
+			}
 		}
-		if dr != nil {
-			r.DataRecords = append(r.DataRecords, dr)
+		if w != nil {
+			w.shift(offset)
+			warnings = append(warnings, w)
 		}
-	} else if strings.Index(line, "=") != -1 && strings.Count(line, "=") == 1 {
-		v, w := parseVarialbe(txt)
+		l.DataRecord = dr
+		l.fields = shiftFields(fields, offset)
+	} else if strings.Contains(line, variableDenote) {
+		v, fields, w := parseVariableFields(line)
 		if w != nil {
-			w.Index = index
-			w.Text = txt
-			r.Warnings = append(r.Warnings, w)
-		} else {
-			r.Variables = append(r.Variables, v)
+			w.shift(offset)
+			warnings = append(warnings, w)
 		}
+		l.Variable = v
+		l.fields = shiftFields(fields, offset)
 	} else {
-		w := &Warning{Text: txt, Index: index, Level: HighSevirity, Message: "could not parse this line"}
-		r.Warnings = append(r.Warnings, w)
+		w := newWarning(CodeUnparseableLine, FatalSevirity, lineField(txt), "could not parse this line", nil)
+		warnings = append(warnings, w)
+	}
+
+	// keep parsed record provenance: line number, raw line text and line comment
+	if l.DataRecord != nil {
+		l.DataRecord.Line, l.DataRecord.Raw, l.DataRecord.Comment = index, txt, comment
+	}
+	if l.Variable != nil {
+		l.Variable.Line, l.Variable.Raw, l.Variable.Comment = index, txt, comment
+	}
+
+	// validate parsed line: once a record is rejected there is no point in running the remaining rules
+	rejected := p.review(l, warnings)
+	for _, rule := range p.rules {
+		if rejected || (l.DataRecord == nil && l.Variable == nil) {
+			break
+		}
+
+		ws := rule.Check(l)
+		warnings = append(warnings, ws...)
+		rejected = p.review(l, ws)
+	}
+
+	if rejected {
+		l.DataRecord = nil
+		l.Variable = nil
+	}
+
+	// accepted records are validated again once the entire Ads.txt file was parsed
+	if l.DataRecord != nil || l.Variable != nil {
+		for _, c := range p.checkers {
+			c.Observe(l)
+		}
+	}
+
+	return l, warnings
+}
+
+// finish return the warnings found by the file level validation rules, once the entire Ads.txt file was parsed
+func (p *parser) finish() []*Warning {
+	warnings := []*Warning{}
+	for _, c := range p.checkers {
+		for _, w := range c.Finish() {
+			w.Level = p.opts.sevirity(w)
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+// review apply the parse options to the warnings found on the specified line, and return true if any of the
+// warnings is severe enough to reject the line record. Warnings suppressed by the comment directives declared so far
+// (on the line itself, the comment line before it, or file level directives declared before it) do not reject the
+// record, errors are suppressed only if the parse options allow it
+func (p *parser) review(l *Line, warnings []*Warning) bool {
+	rejected := false
+	for _, w := range warnings {
+		w.Index = l.Index
+		w.Text = l.Text
+		w.Level = p.opts.sevirity(w)
+		if p.opts.rejects(w) && !p.suppress.suppressed(w) {
+			rejected = true
+		}
+	}
+	return rejected
+}
+
+// SeverityCounts return the number of warnings found when parsing Ads.txt file, by sevirity level
+func (r *Records) SeverityCounts() map[Sevirity]int {
+	counts := map[Sevirity]int{}
+	for _, w := range r.Warnings {
+		counts[w.Level]++
 	}
+	return counts
 }
 
 // custom "toString" method
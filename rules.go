@@ -0,0 +1,301 @@
+package adstxt
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+)
+
+// Built-in validation rules names, used to disable a rule using ParseOptions
+const (
+	// RuleAdSystemDomain validate that data record field #1 is a valid domain name
+	RuleAdSystemDomain = "adsystem-domain"
+	// RuleNormalizedDomain validate that data record field #1 is in its normalized form (IDNA ASCII, lowercase, no trailing dot)
+	RuleNormalizedDomain = "normalized-domain"
+	// RuleKnownAdSystem validate that data record field #1 is the canonical domain of a known ad system
+	RuleKnownAdSystem = "known-adsystem"
+	// RuleCertAuthorityID validate that data record field #4 is alphanumeric
+	RuleCertAuthorityID = "cert-authority-id"
+	// RuleKnownCertAuthorityID validate that data record field #4 is the certification authority ID published by the ad system
+	RuleKnownCertAuthorityID = "known-cert-authority-id"
+	// RuleAccountIDFormat validate that data record field #2 matches the account ID pattern of the ad system
+	RuleAccountIDFormat = "account-id-format"
+	// RuleVariableValue validate that variable value is valid for its type (contact, subdomain, owner and manager domains)
+	RuleVariableValue = "variable-value"
+)
+
+// Line holds single parsed Ads.txt line, handed over to validation rules
+type Line struct {
+	Index      int         // Index of the line in the Ads.txt file
+	Text       string      // Text of the line in the Ads.txt file
+	DataRecord *DataRecord // DataRecord parsed from the line (nil if the line is not a data record)
+	Variable   *Variable   // Variable parsed from the line (nil if the line is not a variable)
+	Registry   *Registry   // Registry of known ad systems the line is validated against
+	Domain     string      // Domain root domain of the Ads.txt file, used to validate subdomain variables (empty if unknown)
+
+	fields []field // position of the data record or variable fields in the line text
+	key    string  // key identifying the ad system of the data record, computed once for all file level rules
+}
+
+// Warn create new warning concerning the specified field (1-based) of the line. Use field 0 for warning
+// concerning the whole line
+func (l *Line) Warn(code string, level Sevirity, fieldIndex int, msg string, params map[string]string) *Warning {
+	f := lineField(l.Text)
+	for _, lf := range l.fields {
+		if lf.index == fieldIndex {
+			f = lf
+			break
+		}
+	}
+
+	w := newWarning(code, level, f, msg, params)
+	w.Index = l.Index
+	w.Text = l.Text
+	return w
+}
+
+// The Rule interface is used to validate parsed Ads.txt lines. Rule is called for each Ads.txt line
+// successfully parsed into a data record or a variable, and returns the warnings found on that line (if any)
+type Rule interface {
+	Name() string             // Name unique name of the rule, used to disable the rule
+	Check(l *Line) []*Warning // Check validate parsed Ads.txt line
+}
+
+// rule is a Rule implementation wrapping a check function
+type rule struct {
+	name  string
+	check func(l *Line) []*Warning
+}
+
+// Name is the Rule interface implementation for the rule type
+func (r *rule) Name() string {
+	return r.name
+}
+
+// Check is the Rule interface implementation for the rule type
+func (r *rule) Check(l *Line) []*Warning {
+	return r.check(l)
+}
+
+// NewRule create new named validation rule from the specified check function
+func NewRule(name string, check func(l *Line) []*Warning) Rule {
+	return &rule{name: name, check: check}
+}
+
+// builtinRules validation rules run on every parsed Ads.txt line, unless disabled
+var builtinRules = []Rule{
+	NewRule(RuleAdSystemDomain, checkAdSystemDomain),
+	NewRule(RuleNormalizedDomain, checkNormalizedDomain),
+	NewRule(RuleKnownAdSystem, checkKnownAdSystem),
+	NewRule(RuleCertAuthorityID, checkCertAuthorityID),
+	NewRule(RuleKnownCertAuthorityID, checkKnownCertAuthorityID),
+	NewRule(RuleAccountIDFormat, checkAccountIDFormat),
+	NewRule(RuleVariableValue, checkVariableValue),
+}
+
+// checkAdSystemDomain validate that data record field #1 is a valid domain name
+func checkAdSystemDomain(l *Line) []*Warning {
+	if l.DataRecord == nil {
+		return nil
+	}
+
+	domain := l.DataRecord.AdverterDomain
+	if !validateDomainName(domain) {
+		return []*Warning{l.Warn(CodeInvalidAdSystemDomain, ErrorSevirity, 1,
+			fmt.Sprintf("%s is not a valid Ad system domain", domain), map[string]string{"domain": domain})}
+	}
+	return nil
+}
+
+// checkNormalizedDomain validate that data record field #1 is in its normalized form: Unicode domain names should
+// be declared in their IDNA ASCII form (punycode), lowercase and without trailing dot
+func checkNormalizedDomain(l *Line) []*Warning {
+	if l.DataRecord == nil {
+		return nil
+	}
+
+	domain := l.DataRecord.AdverterDomain
+	if normalized := normalizeDomain(domain); normalized != domain {
+		return []*Warning{l.Warn(CodeNonNormalizedDomain, InfoSevirity, 1,
+			fmt.Sprintf("%s is not in its normalized form. Please consider using %s", domain, normalized),
+			map[string]string{"domain": domain, "normalized": normalized})}
+	}
+	return nil
+}
+
+// checkKnownAdSystem validate that data record field #1 is the canonical domain of a known ad system. The placeholder
+// record is not an ad system, and is not validated against the registry
+func checkKnownAdSystem(l *Line) []*Warning {
+	if l.DataRecord == nil || l.DataRecord.IsPlaceholder() {
+		return nil
+	}
+
+	domain := l.DataRecord.AdverterDomain
+	err := l.Registry.validate(domain)
+	if err == nil {
+		return nil
+	}
+
+	// non canonical domain of a known ad system is valid, publisher should probably use the canonical domain
+	code, level := CodeUnknownAdSystem, WarningSevirity
+	params := map[string]string{"domain": domain}
+	if e, ok := err.(*adSystemError); ok && e.code == CodeNonCanonicalAdSystem {
+		code, level = e.code, InfoSevirity
+		params["canonical"] = e.canonical
+	}
+	return []*Warning{l.Warn(code, level, 1, err.Error(), params)}
+}
+
+// checkCertAuthorityID validate that data record field #4 is alphanumeric (if not, it might indicate an error also
+// it is not part of Ads.txt specification)
+func checkCertAuthorityID(l *Line) []*Warning {
+	if l.DataRecord == nil || len(l.DataRecord.CertAuthorityID) == 0 {
+		return nil
+	}
+
+	id := l.DataRecord.CertAuthorityID
+	if !isAlphanumeric(id) {
+		return []*Warning{l.Warn(CodeInvalidCertAuthorityID, WarningSevirity, 4,
+			fmt.Sprintf("Certification Authority ID %s may not be correct as it is not alphanumeric", id), map[string]string{"value": id})}
+	}
+	return nil
+}
+
+// checkKnownCertAuthorityID validate that data record field #4 is the certification authority ID published by the ad
+// system (if the ad system is known, and published its certification authority ID)
+func checkKnownCertAuthorityID(l *Line) []*Warning {
+	if l.DataRecord == nil || l.DataRecord.IsPlaceholder() {
+		return nil
+	}
+
+	a, ok := l.Registry.Lookup(l.DataRecord.AdverterDomain)
+	if !ok || len(a.CertAuthorityIDs) == 0 {
+		return nil
+	}
+
+	id := l.DataRecord.CertAuthorityID
+	expected := strings.Join(a.CertAuthorityIDs, ", ")
+	params := map[string]string{"domain": l.DataRecord.AdverterDomain, "expected": expected}
+	switch {
+	case len(id) == 0:
+		return []*Warning{l.Warn(CodeMissingCertAuthorityID, InfoSevirity, 0,
+			fmt.Sprintf("Certification Authority ID is missing, %s published [%s] as its Certification Authority ID", a.Name, expected), params)}
+	case !a.isCertAuthorityID(id):
+		params["value"] = id
+		return []*Warning{l.Warn(CodeCertAuthorityIDMismatch, WarningSevirity, 4,
+			fmt.Sprintf("Certification Authority ID %s does not match [%s] published by %s", id, expected, a.Name), params)}
+	}
+	return nil
+}
+
+// checkAccountIDFormat validate that data record field #2 matches the account ID pattern of the ad system (if the ad
+// system is known, and has account ID pattern)
+func checkAccountIDFormat(l *Line) []*Warning {
+	if l.DataRecord == nil || l.DataRecord.IsPlaceholder() {
+		return nil
+	}
+
+	a, ok := l.Registry.Lookup(l.DataRecord.AdverterDomain)
+	if !ok || a.matchAccountID(l.DataRecord.PublisherAccountID) {
+		return nil
+	}
+
+	id := l.DataRecord.PublisherAccountID
+	return []*Warning{l.Warn(CodeInvalidAccountIDFormat, WarningSevirity, 2,
+		fmt.Sprintf("Account ID %s does not match the account ID format of %s [%s]", id, a.Name, a.AccountIDPattern),
+		map[string]string{"domain": l.DataRecord.AdverterDomain, "value": id, "pattern": a.AccountIDPattern})}
+}
+
+// checkVariableValue validate that variable value is valid for its type: contact should be an email address, URL or
+// phone number, owner and manager domains should be domain names, and subdomain should be a host name within the root
+// domain of the Ads.txt file (subdomains outside the root domain are ignored by crawlers). Variables are reported but
+// never rejected
+func checkVariableValue(l *Line) []*Warning {
+	// variable without value is reported by the parser
+	if l.Variable == nil || len(l.Variable.Value) == 0 {
+		return nil
+	}
+
+	v := l.Variable
+	params := map[string]string{"type": v.Type, "value": v.Value}
+	switch v.Type {
+	case varTypeContact:
+		if !isContact(v.Value) {
+			return []*Warning{l.Warn(CodeInvalidVariableValue, WarningSevirity, 2,
+				fmt.Sprintf("Contact %s may not be correct as it is not an email address, URL or phone number", v.Value), params)}
+		}
+	case varTypeOwnerDomain, varTypeManagerDomain:
+		// manager domain may be followed by the country code of the manager (e.g. "example.com,US")
+		domain := v.Value
+		if i := strings.Index(domain, ","); i != -1 && v.Type == varTypeManagerDomain {
+			domain = strings.TrimSpace(domain[0:i])
+		}
+		if !isValidHost(normalizeDomain(domain)) {
+			return []*Warning{l.Warn(CodeInvalidVariableValue, WarningSevirity, 2,
+				fmt.Sprintf("%s %s is not a valid domain name", v.Type, domain), params)}
+		}
+	case varTypeSubdomain:
+		host := normalizeDomain(v.Value)
+		if !isValidHost(host) {
+			return []*Warning{l.Warn(CodeInvalidVariableValue, WarningSevirity, 2,
+				fmt.Sprintf("Subdomain %s is not a valid host name", v.Value), params)}
+		}
+		if len(l.Domain) > 0 && host != l.Domain && !strings.HasSuffix(host, "."+l.Domain) {
+			params["domain"] = l.Domain
+			return []*Warning{l.Warn(CodeSubdomainOutOfScope, WarningSevirity, 2,
+				fmt.Sprintf("Subdomain %s is not within the root domain %s", v.Value, l.Domain), params)}
+		}
+	}
+	return nil
+}
+
+// isContact check if contact information is an email address, http(s) URL or phone number (including "mailto:"
+// and "tel:" URLs)
+func isContact(contact string) bool {
+	lower := strings.ToLower(contact)
+	switch {
+	case strings.HasPrefix(lower, "mailto:"):
+		return isEmail(contact[len("mailto:"):])
+	case strings.HasPrefix(lower, "tel:"):
+		return isPhoneNumber(contact[len("tel:"):])
+	case strings.HasPrefix(lower, "http://"), strings.HasPrefix(lower, "https://"):
+		u, err := url.Parse(contact)
+		return err == nil && isValidHost(normalizeDomain(u.Hostname()))
+	}
+	return isEmail(contact) || isPhoneNumber(contact)
+}
+
+// isEmail check if the string is an email address (e.g. "adops@example.com" or "Ad Ops <adops@example.com>")
+func isEmail(s string) bool {
+	_, err := mail.ParseAddress(s)
+	return err == nil
+}
+
+// isPhoneNumber check if the string is a phone number: optional "+" prefix, followed by 7 to 15 digits, which may be
+// separated by white spaces, hyphens, dots or parentheses
+func isPhoneNumber(s string) bool {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "+")
+	digits := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case '0' <= c && c <= '9':
+			digits++
+		case c == ' ' || c == '-' || c == '.' || c == '(' || c == ')':
+		default:
+			return false
+		}
+	}
+	return 7 <= digits && digits <= 15
+}
+
+// isAlphanumeric check if all the string characters are ASCII letters or digits
+func isAlphanumeric(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('a' <= c && c <= 'z') && !('A' <= c && c <= 'Z') && !('0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
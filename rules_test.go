@@ -0,0 +1,261 @@
+package adstxt
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// validateLine parse and validate single Ads.txt line using the default parse options, and return the
+// first warning found on that line (if any)
+func validateLine(line string) (*DataRecord, *Warning) {
+	l, warnings := newParser(&ParseOptions{Registry: testRegistry}).parseLine(1, line)
+	if len(warnings) > 0 {
+		return l.DataRecord, warnings[0]
+	}
+	return l.DataRecord, nil
+}
+
+// TestBuiltinRules test built-in rules validating parsed data records
+func TestBuiltinRules(t *testing.T) {
+	tests := map[string]string{
+		"http://greenadexchange.com, XF7342, DIRECT":                 CodeInvalidAdSystemDomain,
+		"example.com, XF7342, DIRECT":                                CodeUnknownAdSystem,
+		"Tremorhub.com, XF7342, DIRECT":                              CodeNonNormalizedDomain,
+		"tremorhub.com, XF7342, DIRECT":                              "",
+		"tremorhub.com., XF7342, DIRECT":                             CodeNonNormalizedDomain,
+		"greenadexchange.com, XF7342, DIRECT, <cert>":                CodeInvalidCertAuthorityID,
+		"greenadexchange.com, XF7342, DIRECT, 5jyxf8k":               "",
+		"google.com, pub-0000000000001234, DIRECT, f08c47fec0942fa0": "",
+		"google.com, pub-0000000000001234, DIRECT, F08C47FEC0942FA0": "",
+		"google.com, pub-1234, DIRECT, f08c47fec0942fa0":             CodeInvalidAccountIDFormat,
+		"rubiconproject.com, 12345, DIRECT, 0bfd66d529a55807":        "",
+		"rubiconproject.com, 12345x, DIRECT, 0bfd66d529a55807":       CodeInvalidAccountIDFormat,
+		"google.com, pub-1234, DIRECT":                               CodeMissingCertAuthorityID,
+		"google.com, pub-1234, DIRECT, 5jyxf8k":                      CodeCertAuthorityIDMismatch,
+	}
+
+	for line, code := range tests {
+		_, w := validateLine(line)
+		if len(code) == 0 && w != nil {
+			t.Errorf("Expected no warning when validating [%s] but recieved [%v]", line, w)
+		}
+		if len(code) > 0 && (w == nil || w.Code != code) {
+			t.Errorf("Expected warning [%s] when validating [%s] but recieved [%v]", code, line, w)
+		}
+	}
+}
+
+// TestKnownCertAuthorityID test validating data record certification authority ID against the ID published by the ad system
+func TestKnownCertAuthorityID(t *testing.T) {
+	_, w := validateLine("google.com, pub-1234, DIRECT, 5jyxf8k")
+	if w == nil || w.Level != WarningSevirity || w.Field != 4 || w.Params["expected"] != "f08c47fec0942fa0" {
+		t.Errorf("Expected certification authority ID mismatch warning on field #4 but recieved [%v]", w)
+	}
+
+	_, w = validateLine("google.com, pub-1234, DIRECT")
+	if w == nil || w.Level != InfoSevirity || w.Params["expected"] != "f08c47fec0942fa0" {
+		t.Errorf("Expected missing certification authority ID info warning but recieved [%v]", w)
+	}
+
+	// ad system which did not publish its certification authority ID
+	r, _ := NewRegistry([]*AdSystem{{ID: 1, Name: "Acme", CanonicalDomains: []string{"acme.com"}}}, nil)
+	res, _ := ParseBodyWithOptions([]byte("acme.com, 1234, DIRECT, 5jyxf8k\nacme.com, 5678, DIRECT"), &ParseOptions{Registry: r})
+	if len(res.Warnings) != 0 {
+		t.Errorf("Expected no warnings for ad system without certification authority ID but recieved [%v]", res.Warnings)
+	}
+}
+
+// TestAccountIDFormat test validating data record account ID against the account ID pattern of the ad system
+func TestAccountIDFormat(t *testing.T) {
+	_, w := validateLine("google.com, pub-12345, DIRECT, f08c47fec0942fa0")
+	if w == nil || w.Code != CodeInvalidAccountIDFormat || w.Field != 2 || w.Params["pattern"] != `pub-\d{16}` {
+		t.Errorf("Expected account ID format warning on field #2 but recieved [%v]", w)
+	}
+
+	// account ID pattern must match the entire account ID
+	r, err := LoadRegistry(strings.NewReader(`{"adSystems": [{"id": 1, "name": "Acme", "accountIdPattern": "[0-9]+|acme-[a-z]+"}],
+		"domains": [{"domain": "acme.com", "id": 1}]}`))
+	if err != nil {
+		t.Fatalf("Failed to load registry: %s", err)
+	}
+
+	tests := map[string]bool{"1234": true, "acme-xyz": true, "1234x": false, "x1234": false, "acme-xyz1": false}
+	for id, valid := range tests {
+		res, _ := ParseBodyWithOptions([]byte("acme.com, "+id+", DIRECT"), &ParseOptions{Registry: r})
+		if valid != (len(res.Warnings) == 0) {
+			t.Errorf("Expected account ID [%s] validity to be [%t] but recieved [%v]", id, valid, res.Warnings)
+		}
+	}
+
+	if _, err := NewRegistry([]*AdSystem{{ID: 1, Name: "Acme", AccountIDPattern: "[0-9"}}, nil); err == nil {
+		t.Error("Expected error when loading registry with invalid account ID pattern")
+	}
+}
+
+// TestVariableValue test validating variable value by variable type, and subdomain scope by the Ads.txt file root domain
+func TestVariableValue(t *testing.T) {
+	tests := []struct {
+		line  string
+		code  string
+		level Sevirity
+	}{
+		{"contact=adops@example.com", "", 0},
+		{"contact=Ad Ops <adops@example.com>", "", 0},
+		{"contact=https://example.com/contact?team=ads", "", 0},
+		{"contact=mailto:adops@example.com", "", 0},
+		{"contact=+1 (555) 010-0000", "", 0},
+		{"contact=tel:+15550100000", "", 0},
+		{"contact=Ad Ops team", CodeInvalidVariableValue, WarningSevirity},
+		{"contact=https://", CodeInvalidVariableValue, WarningSevirity},
+		{"contact=010-00", CodeInvalidVariableValue, WarningSevirity},
+		{"ownerdomain=example.com", "", 0},
+		{"ownerdomain=example com", CodeInvalidVariableValue, WarningSevirity},
+		{"managerdomain=manager.com,US", "", 0},
+		{"managerdomain=https://manager.com", CodeInvalidVariableValue, WarningSevirity},
+		{"subdomain=dev.example.com", "", 0},
+		{"subdomain=example.com", "", 0},
+		{"subdomain=DEV.Example.com.", "", 0},
+		{"subdomain=dev.other.com", CodeSubdomainOutOfScope, WarningSevirity},
+		{"subdomain=badexample.com", CodeSubdomainOutOfScope, WarningSevirity},
+		{"subdomain=dev example.com", CodeInvalidVariableValue, WarningSevirity},
+	}
+
+	for _, test := range tests {
+		l, warnings := newParser(&ParseOptions{Registry: testRegistry, Domain: "example.com"}).parseLine(1, test.line)
+		if len(test.code) == 0 {
+			if len(warnings) != 0 || l.Variable == nil {
+				t.Errorf("Expected no warning when validating [%s] but recieved [%v]", test.line, warnings)
+			}
+			continue
+		}
+		if len(warnings) != 1 || warnings[0].Code != test.code || warnings[0].Level != test.level || warnings[0].Field != 2 {
+			t.Errorf("Expected [%s] warning on field #2 when validating [%s] but recieved [%v]", test.code, test.line, warnings)
+			continue
+		}
+		if rejected := l.Variable == nil; rejected != (test.level >= ErrorSevirity) {
+			t.Errorf("Expected [%s] to be rejected [%t] but recieved [%t]", test.line, test.level >= ErrorSevirity, rejected)
+		}
+	}
+
+	// subdomain scope is not validated if the Ads.txt file root domain is unknown
+	_, w := validateLine("subdomain=dev.other.com")
+	if w != nil {
+		t.Errorf("Expected subdomain scope not to be validated without root domain but recieved [%v]", w)
+	}
+}
+
+// TestParseOptionsDisabledRules test disabling validation rules using parse options
+func TestParseOptionsDisabledRules(t *testing.T) {
+	b := []byte("example.com, XF7342, DIRECT, <cert>")
+
+	res, _ := ParseBodyWithOptions(b, &ParseOptions{KeepUnknownAdSystems: true})
+	if len(res.Warnings) != 2 {
+		t.Errorf("Expected 2 warnings when parsing [%s] but recieved [%d]", string(b), len(res.Warnings))
+	}
+
+	opts := &ParseOptions{DisabledRules: []string{RuleKnownAdSystem, RuleCertAuthorityID}}
+	res, _ = ParseBodyWithOptions(b, opts)
+	if len(res.Warnings) != 0 {
+		t.Errorf("Expected no warnings when parsing [%s] with disabled rules but recieved [%d]", string(b), len(res.Warnings))
+	}
+	if len(res.DataRecords) != 1 {
+		t.Errorf("Expected single DataRecord when parsing [%s] but recieved [%d]", string(b), len(res.DataRecords))
+	}
+}
+
+// TestParseOptionsSeverities test overriding warnings sevirity level using parse options
+func TestParseOptionsSeverities(t *testing.T) {
+	b := []byte("example.com, XF7342, DIRECT\nhttp://greenadexchange.com, XF7342, DIRECT")
+
+	res, _ := ParseBodyWithOptions(b, &ParseOptions{KeepUnknownAdSystems: true})
+	if len(res.DataRecords) != 1 {
+		t.Errorf("Expected single DataRecord when parsing [%s] but recieved [%d]", string(b), len(res.DataRecords))
+	}
+
+	// unknown ad system is rejected, even if requested to be kept
+	opts := &ParseOptions{Severities: map[string]Sevirity{CodeUnknownAdSystem: HighSevirity}, KeepUnknownAdSystems: true}
+	res, _ = ParseBodyWithOptions(b, opts)
+	if len(res.DataRecords) != 0 {
+		t.Errorf("Expected no DataRecord to be accepted when overriding sevirity but recieved [%v]", res.DataRecords)
+	}
+
+	for _, w := range res.Warnings {
+		if w.Code == CodeUnknownAdSystem && w.Level != HighSevirity {
+			t.Errorf("Expected [%s] warning sevirity to be overriden to [%d] but recieved [%d]", w.Code, HighSevirity, w.Level)
+		}
+	}
+
+	// invalid domain name is accepted
+	opts = &ParseOptions{
+		Severities:    map[string]Sevirity{CodeInvalidAdSystemDomain: LowSevirity},
+		DisabledRules: []string{RuleKnownAdSystem},
+	}
+	res, _ = ParseBodyWithOptions(b, opts)
+	if len(res.DataRecords) != 2 {
+		t.Errorf("Expected 2 DataRecords to be accepted when overriding sevirity but recieved [%d]", len(res.DataRecords))
+	}
+
+	if len(res.Warnings) != 1 || res.Warnings[0].Level != LowSevirity {
+		t.Errorf("Expected [%s] warning sevirity to be overriden to [%d] but recieved [%v]", CodeInvalidAdSystemDomain, LowSevirity, res.Warnings)
+	}
+}
+
+// TestParseOptionsKeepUnknownAdSystems test keeping data records of unknown ad systems and non canonical ad system domains
+func TestParseOptionsKeepUnknownAdSystems(t *testing.T) {
+	b := []byte("example.com, XF7342, DIRECT\nadtech.net, XF7342, DIRECT\ngreenadexchange.com, XF7342, DIRECT")
+
+	// data records are rejected by default
+	res, _ := ParseBodyWithOptions(b, &ParseOptions{Registry: testRegistry})
+	if len(res.DataRecords) != 1 || len(res.Warnings) != 2 {
+		t.Errorf("Expected single DataRecord and 2 warnings when parsing [%s] but recieved [%d] and [%v]", string(b), len(res.DataRecords), res.Warnings)
+	}
+
+	// and kept, with the same warnings, when requested
+	res, _ = ParseBodyWithOptions(b, &ParseOptions{Registry: testRegistry, KeepUnknownAdSystems: true})
+	if len(res.DataRecords) != 3 || len(res.Warnings) != 2 {
+		t.Errorf("Expected 3 DataRecords and 2 warnings when parsing [%s] but recieved [%d] and [%v]", string(b), len(res.DataRecords), res.Warnings)
+	}
+	for _, w := range res.Warnings {
+		if w.Code != CodeUnknownAdSystem && w.Code != CodeNonCanonicalAdSystem {
+			t.Errorf("Expected unknown or non canonical ad system warning but recieved [%s]", w.Code)
+		}
+	}
+}
+
+// TestParseOptionsCustomRules test validating Ads.txt file using custom rules
+func TestParseOptionsCustomRules(t *testing.T) {
+	sellerID := regexp.MustCompile("^XF[0-9]+$")
+	r := NewRule("seller-id", func(l *Line) []*Warning {
+		if l.DataRecord == nil || sellerID.MatchString(l.DataRecord.PublisherAccountID) {
+			return nil
+		}
+		return []*Warning{l.Warn("ACME_INVALID_SELLER_ID", LowSevirity, 2,
+			fmt.Sprintf("[%s] is not a valid seller ID", l.DataRecord.PublisherAccountID), nil)}
+	})
+
+	b := []byte("greenadexchange.com, XF7342, DIRECT\n  greenadexchange.com, 7342, DIRECT # invalid seller")
+	res, _ := ParseBodyWithOptions(b, &ParseOptions{Rules: []Rule{r}, Registry: testRegistry})
+
+	if len(res.Warnings) != 1 {
+		t.Fatalf("Expected single warning when parsing [%s] with custom rule but recieved [%d]", string(b), len(res.Warnings))
+	}
+
+	w := res.Warnings[0]
+	if w.Code != "ACME_INVALID_SELLER_ID" || w.Index != 2 || w.Field != 2 {
+		t.Errorf("Expected custom rule warning for line #2 field #2 but recieved [%v]", w)
+	}
+	if w.Text[w.Column-1:w.EndColumn-1] != "7342" {
+		t.Errorf("Expected custom rule warning column span to point to [7342] but points to [%s]", w.Text[w.Column-1:w.EndColumn-1])
+	}
+	if len(res.DataRecords) != 2 {
+		t.Errorf("Expected 2 DataRecords when parsing [%s] but recieved [%d]", string(b), len(res.DataRecords))
+	}
+
+	// custom rule can be disabled like any built-in rule
+	res, _ = ParseBodyWithOptions(b, &ParseOptions{Rules: []Rule{r}, DisabledRules: []string{"seller-id"}, Registry: testRegistry})
+	if len(res.Warnings) != 0 {
+		t.Errorf("Expected no warnings when custom rule is disabled but recieved [%d]", len(res.Warnings))
+	}
+}
@@ -0,0 +1,138 @@
+package adstxt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Public suffix list load error
+const (
+	errSuffixListRule  = "Failed to load public suffix list: invalid rule [%s] on line [%d]"
+	errSuffixListEmpty = "Failed to load public suffix list: list has no rules"
+)
+
+// Public suffix list sections markers
+const (
+	suffixListICANNBegin   = "===BEGIN ICANN DOMAINS==="
+	suffixListICANNEnd     = "===END ICANN DOMAINS==="
+	suffixListPrivateBegin = "===BEGIN PRIVATE DOMAINS==="
+)
+
+// SuffixList Public Suffix List (https://publicsuffix.org) loaded at runtime, used by DomainResolver instead of the
+// list compiled into golang.org/x/net/publicsuffix. Rules are kept by their type, mapped to true if the rule is
+// declared in the ICANN section of the list and to false if declared in the private domains section
+type SuffixList struct {
+	suffixes   map[string]bool // suffixes plain rules (e.g. "co.uk")
+	wildcards  map[string]bool // wildcards wildcard rules by their parent domain (e.g. "ck" for "*.ck")
+	exceptions map[string]bool // exceptions exception rules (e.g. "www.ck" for "!www.ck")
+}
+
+// LoadSuffixList load public suffix list from reader, in the standard public_suffix_list.dat format: a single rule per
+// line (only the text up to the first white space is read), comments start with "//", and ICANN and private domains
+// sections are delimited by "===BEGIN ICANN DOMAINS===" and "===BEGIN PRIVATE DOMAINS===" comments. Rules declared
+// outside of the private domains section are considered ICANN rules
+func LoadSuffixList(rd io.Reader) (*SuffixList, error) {
+	l := &SuffixList{suffixes: map[string]bool{}, wildcards: map[string]bool{}, exceptions: map[string]bool{}}
+
+	icann := true
+	scanner := bufio.NewScanner(rd)
+	for index := 1; scanner.Scan(); index++ {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "//") {
+			switch {
+			case strings.Contains(line, suffixListICANNBegin):
+				icann = true
+			case strings.Contains(line, suffixListICANNEnd), strings.Contains(line, suffixListPrivateBegin):
+				icann = false
+			}
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if !l.add(fields[0], icann) {
+			return nil, fmt.Errorf(errSuffixListRule, fields[0], index)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(l.suffixes)+len(l.wildcards)+len(l.exceptions) == 0 {
+		return nil, fmt.Errorf(errSuffixListEmpty)
+	}
+	return l, nil
+}
+
+// LoadSuffixListFile load public suffix list from file (e.g. public_suffix_list.dat downloaded from publicsuffix.org)
+func LoadSuffixListFile(path string) (*SuffixList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return LoadSuffixList(f)
+}
+
+// add public suffix list rule, return false if the rule is not valid. Rules are normalized the same way as hosts
+// are (IDNA ASCII form, lowercase)
+func (l *SuffixList) add(rule string, icann bool) bool {
+	rules := l.suffixes
+	switch {
+	case strings.HasPrefix(rule, "!"):
+		rules, rule = l.exceptions, rule[1:]
+	case strings.HasPrefix(rule, "*."):
+		rules, rule = l.wildcards, rule[2:]
+	}
+
+	rule = normalizeDomain(rule)
+	if len(rule) == 0 || strings.ContainsAny(rule, "*!") || strings.HasPrefix(rule, ".") || strings.Contains(rule, "..") {
+		return false
+	}
+	rules[rule] = icann
+	return true
+}
+
+// PublicSuffix return the public suffix of the domain, and whether the matching rule is an ICANN rule. Public suffix
+// is determined by the standard algorithm: exception rules take precedence, otherwise the longest matching rule is
+// used, and the top level domain is the public suffix of domains no rule matches (the implicit "*" rule)
+func (l *SuffixList) PublicSuffix(domain string) (string, bool) {
+	domain = normalizeDomain(domain)
+
+	// exception rule match: public suffix is the exception rule with its leftmost label removed
+	for d := domain; len(d) > 0; d = parentDomain(d) {
+		if icann, ok := l.exceptions[d]; ok {
+			return parentDomain(d), icann
+		}
+	}
+
+	// longest matching rule: domains are checked from the longest to the shortest
+	for d := domain; len(d) > 0; d = parentDomain(d) {
+		if icann, ok := l.suffixes[d]; ok {
+			return d, icann
+		}
+		if icann, ok := l.wildcards[parentDomain(d)]; ok {
+			return d, icann
+		}
+	}
+
+	// implicit "*" rule
+	if i := strings.LastIndex(domain, "."); i != -1 {
+		return domain[i+1:], false
+	}
+	return domain, false
+}
+
+// parentDomain return domain with its leftmost label removed, empty string for a top level domain
+func parentDomain(domain string) string {
+	if i := strings.Index(domain, "."); i != -1 {
+		return domain[i+1:]
+	}
+	return ""
+}
@@ -0,0 +1,122 @@
+package adstxt
+
+import (
+	"strings"
+	"testing"
+)
+
+// testSuffixList public suffix list sample, in the standard public_suffix_list.dat format
+const testSuffixList = `// This Source Code Form is subject to the terms of the Mozilla Public License
+
+// ===BEGIN ICANN DOMAINS===
+
+// com : https://en.wikipedia.org/wiki/.com
+com
+uk
+co.uk
+
+// ck : https://en.wikipedia.org/wiki/.ck
+*.ck
+!www.ck
+
+// de
+de
+// newly delegated suffix, not part of the compiled-in list
+example
+
+// ===END ICANN DOMAINS===
+// ===BEGIN PRIVATE DOMAINS===
+
+// Amazon
+s3.amazonaws.com
+*.compute.amazonaws.com
+// newly added private suffix
+hosting.example.com   this text is ignored
+
+// ===END PRIVATE DOMAINS===
+`
+
+// TestSuffixListPublicSuffix test looking up public suffix using a public suffix list loaded at runtime
+func TestSuffixListPublicSuffix(t *testing.T) {
+	l, err := LoadSuffixList(strings.NewReader(testSuffixList))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	suffixes := map[string]struct {
+		suffix string
+		icann  bool
+	}{
+		"example.com":                   {"com", true},
+		"www.example.co.uk":             {"co.uk", true},
+		"WWW.Example.CO.UK.":            {"co.uk", true},
+		"www.test.ck":                   {"test.ck", true},
+		"www.ck":                        {"ck", true},
+		"shop.example":                  {"example", true},
+		"bucket.s3.amazonaws.com":       {"s3.amazonaws.com", false},
+		"a.b.compute.amazonaws.com":     {"b.compute.amazonaws.com", false},
+		"publisher.hosting.example.com": {"hosting.example.com", false},
+		"example.unknown":               {"unknown", false},
+		"xn--bcher-kva.de":              {"de", true},
+	}
+
+	for k, v := range suffixes {
+		suffix, icann := l.PublicSuffix(k)
+		if suffix != v.suffix || icann != v.icann {
+			t.Errorf("Expected [%s] public suffix to be [%s] (ICANN %t) but recieved [%s] (ICANN %t)", k, v.suffix, v.icann, suffix, icann)
+		}
+	}
+}
+
+// TestSuffixListRootDomain test resolving root domain using a public suffix list loaded at runtime
+func TestSuffixListRootDomain(t *testing.T) {
+	l, err := LoadSuffixList(strings.NewReader(testSuffixList))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	domains := map[string]string{
+		"http://www.shop.example/ads.txt":        "shop.example",
+		"https://publisher.hosting.example.com/": "publisher.hosting.example.com",
+		"www.example.co.uk":                      "example.co.uk",
+	}
+
+	r := &DomainResolver{Suffixes: l}
+	for k, v := range domains {
+		res, err := r.RootDomain(k)
+		if err != nil {
+			t.Error(err)
+		}
+		if res != v {
+			t.Errorf("Expected [%s] root domain to be [%s] and not [%s]", k, v, res)
+		}
+	}
+
+	r.ICANNOnly = true
+	if res, _ := r.RootDomain("publisher.hosting.example.com"); res != "example.com" {
+		t.Errorf("Expected ICANN only root domain to be [example.com] and not [%s]", res)
+	}
+	if _, err := r.RootDomain("hosting.example"); err != nil {
+		t.Error(err)
+	}
+	if _, err := r.RootDomain("co.uk"); err == nil {
+		t.Error("Expected root domain of public suffix [co.uk] to fail")
+	}
+}
+
+// TestLoadSuffixListError test loading invalid public suffix list
+func TestLoadSuffixListError(t *testing.T) {
+	lists := []string{
+		"",
+		"// comments only\n\n",
+		"com\n*.\n",
+		"com\nfoo..com\n",
+		"com\nfoo.*.com\n",
+	}
+
+	for _, list := range lists {
+		if _, err := LoadSuffixList(strings.NewReader(list)); err == nil {
+			t.Errorf("Expected loading public suffix list [%s] to fail", list)
+		}
+	}
+}
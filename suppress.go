@@ -0,0 +1,154 @@
+package adstxt
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Warning suppression directives, declared in Ads.txt file comments
+const (
+	// Suppress warnings on the line the directive is declared on, or on the next line when declared on a comment only line
+	directiveIgnore = "adstxt:ignore"
+	// Suppress warnings on the entire Ads.txt file
+	directiveIgnoreFile = "adstxt:ignore-file"
+	// Suppress all warnings when no warning code is specified
+	allCodes = "*"
+)
+
+// codeSet set of suppressed warning codes
+type codeSet map[string]bool
+
+// match check if the set includes the specified warning code
+func (s codeSet) match(code string) bool {
+	return s[allCodes] || s[code]
+}
+
+// merge add the codes of another set to the set, creating the set if needed
+func (s codeSet) merge(other codeSet) codeSet {
+	if s == nil {
+		s = codeSet{}
+	}
+	for code := range other {
+		s[code] = true
+	}
+	return s
+}
+
+// suppressions holds warning suppression directives declared in Ads.txt file comments
+type suppressions struct {
+	file   codeSet         // codes suppressed on the entire file
+	lines  map[int]codeSet // codes suppressed by line index
+	next   codeSet         // codes suppressed on the next line (declared on comment only line)
+	errors bool            // errors directives may suppress errors (ErrorSevirity or above)
+}
+
+// newSuppressions create new empty collection of suppression directives. Errors are suppressed only if requested, so
+// that Ads.txt file can not hide its own invalid records
+func newSuppressions(errors bool) *suppressions {
+	return &suppressions{lines: map[int]codeSet{}, errors: errors}
+}
+
+// observe collect the suppression directives declared on single Ads.txt line
+func (s *suppressions) observe(index int, txt string) {
+	content, comment := splitComment(txt)
+	directive, codes := parseDirective(comment)
+
+	switch {
+	case directive == directiveIgnoreFile:
+		s.file = s.file.merge(codes)
+	case directive == directiveIgnore && len(content) == 0:
+		s.next = s.next.merge(codes)
+		return
+	case directive == directiveIgnore:
+		s.lines[index] = s.lines[index].merge(codes)
+	}
+
+	// directive declared on comment only line applies to the next line with content
+	if len(content) > 0 && s.next != nil {
+		s.lines[index] = s.lines[index].merge(s.next)
+		s.next = nil
+	}
+}
+
+// suppressed check if the warning is suppressed by any of the directives
+func (s *suppressions) suppressed(w *Warning) bool {
+	if w.Level >= ErrorSevirity && !s.errors {
+		return false
+	}
+	return s.file.match(w.Code) || s.lines[w.Index].match(w.Code)
+}
+
+// apply split warnings into reported and suppressed warnings
+func (s *suppressions) apply(warnings []*Warning) ([]*Warning, []*Warning) {
+	reported := []*Warning{}
+	suppressed := []*Warning{}
+	for _, w := range warnings {
+		if s.suppressed(w) {
+			w.Suppressed = true
+			suppressed = append(suppressed, w)
+		} else {
+			reported = append(reported, w)
+		}
+	}
+	return reported, suppressed
+}
+
+// parseDirective parse suppression directive and the list of suppressed warning codes from Ads.txt comment
+func parseDirective(comment string) (string, codeSet) {
+	// directive is matched case insensitive: comment is lowercased byte by byte, so that the directive index is the
+	// same in the original comment (strings.ToLower may change the length of text which is not valid UTF-8)
+	lower := asciiToLower(comment)
+	index := strings.Index(lower, directiveIgnore)
+	if index == -1 {
+		return "", nil
+	}
+
+	directive := directiveIgnore
+	rest := comment[index+len(directiveIgnore):]
+	if strings.HasPrefix(lower[index+len(directiveIgnore):], directiveIgnoreFile[len(directiveIgnore):]) {
+		directive = directiveIgnoreFile
+		rest = rest[len(directiveIgnoreFile)-len(directiveIgnore):]
+	}
+
+	// directive must be followed by white space, list of codes or nothing at all
+	if len(rest) > 0 && !unicode.IsSpace(rune(rest[0])) {
+		return "", nil
+	}
+
+	// directive without anything following it suppress all warnings
+	tokens := strings.FieldsFunc(rest, func(r rune) bool { return r == ',' || unicode.IsSpace(r) })
+	if len(tokens) == 0 {
+		return directive, codeSet{allCodes: true}
+	}
+
+	// warning codes are separated by white spaces or commas, any other text ends the list of codes. Directive followed
+	// by text which is not a warning code is ignored, rather than suppressing all warnings
+	codes := codeSet{}
+	for _, code := range tokens {
+		if strings.TrimFunc(code, isCodeRune) != "" {
+			break
+		}
+		codes[code] = true
+	}
+
+	if len(codes) == 0 {
+		return "", nil
+	}
+	return directive, codes
+}
+
+// isCodeRune check if rune is a valid warning code character
+func isCodeRune(r rune) bool {
+	return ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') || r == '_'
+}
+
+// asciiToLower lowercase ASCII letters only, keeping the text length
+func asciiToLower(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
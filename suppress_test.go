@@ -0,0 +1,171 @@
+package adstxt
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseDirective test parsing warning suppression directives from Ads.txt comments
+func TestParseDirective(t *testing.T) {
+	tests := []struct {
+		comment   string
+		directive string
+		codes     []string
+	}{
+		{"adstxt:ignore ADSTXT_NON_CANONICAL_AD_SYSTEM", directiveIgnore, []string{"ADSTXT_NON_CANONICAL_AD_SYSTEM"}},
+		{"legacy domain adstxt:ignore ADSTXT_UNKNOWN_AD_SYSTEM, ADSTXT_DUPLICATE_RECORD on purpose", directiveIgnore,
+			[]string{"ADSTXT_UNKNOWN_AD_SYSTEM", "ADSTXT_DUPLICATE_RECORD"}},
+		{"adstxt:ignore-file ADSTXT_NO_DATA_RECORDS", directiveIgnoreFile, []string{"ADSTXT_NO_DATA_RECORDS"}},
+		{"adstxt:ignore", directiveIgnore, []string{allCodes}},
+		{"ADSTXT:IGNORE-FILE", directiveIgnoreFile, []string{allCodes}},
+		{"adstxt:ignored ADSTXT_NO_DATA_RECORDS", "", nil},
+		{"adstxt:ignore because reasons", "", nil},
+		{"adstxt:ignore-file, legacy ADSTXT_UNKNOWN_AD_SYSTEM", "", nil},
+		{"just a comment", "", nil},
+		{"0000\x870AdstXt:ignore ADSTXT_UNKNOWN_AD_SYSTEM", directiveIgnore, []string{"ADSTXT_UNKNOWN_AD_SYSTEM"}},
+	}
+
+	for _, test := range tests {
+		directive, codes := parseDirective(test.comment)
+		if directive != test.directive {
+			t.Errorf("Expected directive of [%s] to be [%s] but recieved [%s]", test.comment, test.directive, directive)
+		}
+		if len(codes) != len(test.codes) {
+			t.Errorf("Expected [%d] codes for [%s] but recieved [%v]", len(test.codes), test.comment, codes)
+		}
+		for _, code := range test.codes {
+			if !codes[code] {
+				t.Errorf("Expected [%s] code to be suppressed by [%s]", code, test.comment)
+			}
+		}
+	}
+}
+
+// TestSuppressWarnings test suppressing parse warnings using comment directives
+func TestSuppressWarnings(t *testing.T) {
+	body := []string{
+		"adtech.net, 100, DIRECT # adstxt:ignore ADSTXT_NON_CANONICAL_AD_SYSTEM",
+		"adtech.net, 101, DIRECT",
+		"# adstxt:ignore ADSTXT_UNKNOWN_AD_SYSTEM",
+		"",
+		"example.com, 100, DIRECT",
+		"example.com, 101, DIRECT",
+		"example.com, 102, DIRECT # adstxt:ignore",
+	}
+	b := []byte(strings.Join(body, "\n"))
+
+	res, _ := ParseBodyWithOptions(b, &ParseOptions{KeepUnknownAdSystems: true})
+	if len(res.Warnings) != 2 {
+		t.Fatalf("Expected 2 warnings when parsing Ads.txt with suppression directives but recieved [%d]", len(res.Warnings))
+	}
+	if res.Warnings[0].Index != 2 || res.Warnings[1].Index != 6 {
+		t.Errorf("Expected warnings on lines #2 and #6 but recieved lines #%d and #%d", res.Warnings[0].Index, res.Warnings[1].Index)
+	}
+	if len(res.Suppressed) != 0 {
+		t.Errorf("Expected suppressed warnings to be omitted unless requested but recieved [%d]", len(res.Suppressed))
+	}
+
+	// suppressed warnings are available when requested
+	res, _ = ParseBodyWithOptions(b, &ParseOptions{KeepUnknownAdSystems: true, IncludeSuppressed: true})
+	if len(res.Suppressed) != 3 {
+		t.Fatalf("Expected 3 suppressed warnings but recieved [%d]", len(res.Suppressed))
+	}
+	for _, w := range res.Suppressed {
+		if !w.Suppressed {
+			t.Errorf("Expected warning on line #%d to be marked as suppressed", w.Index)
+		}
+	}
+
+	// suppressing warnings does not change the parsed records
+	if len(res.DataRecords) != 5 {
+		t.Errorf("Expected 5 DataRecords but recieved [%d]", len(res.DataRecords))
+	}
+}
+
+// TestSuppressFileWarnings test suppressing warnings on the entire Ads.txt file
+func TestSuppressFileWarnings(t *testing.T) {
+	body := []string{
+		"example.com, 100, DIRECT",
+		"example.com, 100, DIRECT",
+		"example.com, 101, DIRECT, <cert>",
+		"# adstxt:ignore-file ADSTXT_UNKNOWN_AD_SYSTEM ADSTXT_DUPLICATE_RECORD",
+	}
+
+	res, _ := ParseBodyWithOptions([]byte(strings.Join(body, "\n")), &ParseOptions{Strict: true, KeepUnknownAdSystems: true, IncludeSuppressed: true})
+	if len(res.Warnings) != 1 || res.Warnings[0].Code != CodeInvalidCertAuthorityID {
+		t.Errorf("Expected only [%s] warning to be reported but recieved [%v]", CodeInvalidCertAuthorityID, res.Warnings)
+	}
+	if len(res.Suppressed) != 4 {
+		t.Errorf("Expected 4 suppressed warnings but recieved [%d]", len(res.Suppressed))
+	}
+
+	// errors are not suppressed unless requested, and fail strict mode
+	b := []byte("# adstxt:ignore-file\nnot a valid line\nexample.com, 100, DIRECT")
+	if _, err := ParseBodyWithOptions(b, &ParseOptions{Strict: true}); err == nil {
+		t.Error("Expected error in strict mode when errors are suppressed without SuppressErrors")
+	}
+
+	res, err := ParseBodyWithOptions(b, &ParseOptions{Strict: true, SuppressErrors: true})
+	if err != nil {
+		t.Errorf("Expected no error in strict mode when all warnings are suppressed [%s]", err.Error())
+	}
+	if len(res.Warnings) != 0 {
+		t.Errorf("Expected no warnings when all warnings are suppressed but recieved [%d]", len(res.Warnings))
+	}
+}
+
+// TestSuppressErrors test errors are suppressed only if requested: records with errors are rejected and fail strict
+// mode, whatever directives the Ads.txt file declares
+func TestSuppressErrors(t *testing.T) {
+	body := []string{
+		"greenadexchange.com, 100, DIRECT, <cert> # adstxt:ignore ADSTXT_INVALID_CERT_AUTHORITY_ID",
+		"# adstxt:ignore",
+		"greenadexchange.com, 101, DIRECT, <cert>",
+		"greenadexchange.com, 102, DIRECT, <cert>",
+		"example.com, 103, DIRECT # adstxt:ignore ADSTXT_UNKNOWN_AD_SYSTEM",
+	}
+	opts := &ParseOptions{Registry: testRegistry, Severities: map[string]Sevirity{CodeInvalidCertAuthorityID: ErrorSevirity}, IncludeSuppressed: true}
+
+	res, _ := ParseBodyWithOptions([]byte(strings.Join(body, "\n")), opts)
+	if len(res.DataRecords) != 1 || res.DataRecords[0].Line != 5 {
+		t.Errorf("Expected only DataRecord with suppressed warning on line #5 to be kept but recieved [%v]", res.DataRecords)
+	}
+	if len(res.Warnings) != 3 || res.Warnings[0].Index != 1 || res.Warnings[1].Index != 3 || res.Warnings[2].Index != 4 {
+		t.Errorf("Expected errors on lines #1, #3 and #4 to be reported but recieved [%v]", res.Warnings)
+	}
+	if len(res.Suppressed) != 1 {
+		t.Errorf("Expected single suppressed warning but recieved [%d]", len(res.Suppressed))
+	}
+
+	// errors are suppressed when requested: records are kept
+	opts.SuppressErrors = true
+	res, _ = ParseBodyWithOptions([]byte(strings.Join(body, "\n")), opts)
+	if len(res.DataRecords) != 3 || res.DataRecords[0].Line != 1 || res.DataRecords[1].Line != 3 || res.DataRecords[2].Line != 5 {
+		t.Errorf("Expected DataRecords with suppressed errors on lines #1, #3 and #5 to be kept but recieved [%v]", res.DataRecords)
+	}
+	if len(res.Warnings) != 1 || res.Warnings[0].Index != 4 || res.Warnings[0].Level != ErrorSevirity {
+		t.Errorf("Expected single error on line #4 but recieved [%v]", res.Warnings)
+	}
+	if len(res.Suppressed) != 3 {
+		t.Errorf("Expected 3 suppressed warnings but recieved [%d]", len(res.Suppressed))
+	}
+}
+
+// TestSuppressErrorsStrict test Ads.txt file can not hide its own invalid records from strict mode
+func TestSuppressErrorsStrict(t *testing.T) {
+	b := []byte("http://evil/x, 1, DIRECT # adstxt:ignore")
+
+	res, err := ParseBodyWithOptions(b, &ParseOptions{Strict: true})
+	if _, ok := err.(*ValidationError); !ok {
+		t.Errorf("Expected ValidationError when parsing [%s] in strict mode but recieved [%v]", string(b), err)
+	}
+	if res != nil && len(res.DataRecords) != 0 {
+		t.Errorf("Expected invalid DataRecord to be rejected when parsing [%s] but recieved [%v]", string(b), res.DataRecords)
+	}
+
+	// directive followed by text which is not a warning code suppress nothing
+	res, _ = ParseBodyWithOptions([]byte("example.com, 100, DIRECT # adstxt:ignore because reasons"), &ParseOptions{KeepUnknownAdSystems: true})
+	if len(res.Warnings) != 1 || res.Warnings[0].Code != CodeUnknownAdSystem {
+		t.Errorf("Expected [%s] warning not to be suppressed but recieved [%v]", CodeUnknownAdSystem, res.Warnings)
+	}
+}
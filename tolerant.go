@@ -0,0 +1,140 @@
+package adstxt
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tolerantDelimiters delimiters commonly used instead of comma to separate data record fields, repaired in tolerant
+// parsing mode
+var tolerantDelimiters = []string{"\t", ";", "|"}
+
+// repairDataRecord repair common format mistakes of Ads.txt data record line (comment removed) when parsing in
+// tolerant mode: line wrapped in quotes, fields separated by tab, semicolon or pipe instead of comma, and field #1
+// declared as URL instead of domain name. Return the repaired line, the position of each of the data record fields in
+// the original line, and a warning for each repair made, or the line as is and no fields and warnings if the line
+// could not be repaired into a data record
+func repairDataRecord(line string) (string, []field, []*Warning) {
+	warnings := []*Warning{}
+	repaired, offset := line, 0
+
+	if unquoted, start, ok := repairQuotes(repaired); ok {
+		warnings = append(warnings, newWarning(CodeRepairedQuotes, WarningSevirity, lineField(line),
+			"Data record is wrapped in quotes. Quotes were removed in tolerant parsing mode", nil))
+		repaired, offset = unquoted, start
+	}
+
+	content, _ := splitExtension(repaired)
+	fields := splitFields(content, ",")
+	if delimited, sep, split, ok := repairDelimiter(repaired); ok {
+		warnings = append(warnings, newWarning(CodeRepairedDelimiter, WarningSevirity, lineField(line),
+			fmt.Sprintf("Data record fields are separated by [%q] instead of comma. Fields were split by [%q] in tolerant parsing mode", sep, sep),
+			map[string]string{"delimiter": sep}))
+		repaired, fields = delimited, split
+	}
+
+	// only lines which can be parsed into data record once repaired are repaired
+	if strings.Count(repaired, ",") < 2 || isVariable(repaired) {
+		return line, nil, nil
+	}
+
+	if domain, host, ok := repairAdSystemDomain(repaired); ok {
+		warnings = append(warnings, newWarning(CodeRepairedAdSystemDomain, WarningSevirity, lineField(line),
+			fmt.Sprintf("Domain name of the advertising system [%s] is declared as URL. Domain name [%s] was used in tolerant parsing mode", domain, host),
+			map[string]string{"value": domain, "domain": host}))
+		repaired = host + repaired[strings.Index(repaired, ","):]
+	}
+
+	if len(warnings) == 0 {
+		return repaired, nil, warnings
+	}
+	return repaired, shiftFields(fields, offset), warnings
+}
+
+// repairQuotes return the line without the double or single quotes wrapping it, and the position (0-based) of the
+// unquoted line in the line. Return false if the line is not quoted
+func repairQuotes(line string) (string, int, bool) {
+	if len(line) < 2 || (line[0] != '"' && line[0] != '\'') || line[len(line)-1] != line[0] {
+		return line, 0, false
+	}
+
+	inner := line[1 : len(line)-1]
+	return strings.TrimSpace(inner), 1 + len(inner) - len(strings.TrimLeftFunc(inner, unicode.IsSpace)), true
+}
+
+// repairDelimiter return the line with its fields separated by comma, the delimiter which was used instead and the
+// position of each of the fields in the line. Delimiter is repaired only if the line has no commas, and splitting the
+// line by the delimiter results in 3 or 4 fields with a valid account type as field #3. Consecutive tabs (used to
+// align fields) are treated as a single delimiter
+func repairDelimiter(line string) (string, string, []field, bool) {
+	if strings.Contains(line, ",") {
+		return line, "", nil, false
+	}
+
+	for _, sep := range tolerantDelimiters {
+		content, ext := line, ""
+		if sep != extensionDenote {
+			var f field
+			if content, f = splitExtension(line); len(f.value) > 0 {
+				ext = extensionDenote + " " + f.value
+			}
+		}
+
+		fields, values := []field{}, []string{}
+		for _, f := range splitFields(content, sep) {
+			if len(f.value) > 0 || sep != "\t" {
+				f.index = len(fields) + 1
+				fields, values = append(fields, f), append(values, f.value)
+			}
+		}
+
+		if len(fields) < 3 || len(fields) > 4 {
+			continue
+		}
+		if t := strings.ToUpper(values[2]); t != accountTypeDirect && t != accountTypeReseller {
+			continue
+		}
+		return strings.Join(values, ", ") + ext, sep, fields, true
+	}
+
+	return line, "", nil, false
+}
+
+// repairedFields return the position of the fields parsed from repaired data record line in the original line: each
+// field is mapped to the field at the same index in the original line, fields which can not be mapped (e.g.
+// extension) span the entire line
+func repairedFields(fields []field, original []field, line string) []field {
+	for i := range fields {
+		fields[i].column, fields[i].endColumn = repairedColumns(fields[i].index, original, line)
+	}
+	return fields
+}
+
+// repairedColumns return the column span in the original line of the field at the specified index of the repaired
+// data record line
+func repairedColumns(index int, original []field, line string) (int, int) {
+	for _, f := range original {
+		if index > 0 && f.index == index {
+			return f.column, f.endColumn
+		}
+	}
+	f := lineField(line)
+	return f.column, f.endColumn
+}
+
+// repairAdSystemDomain return data record field #1 and the host name it declares, false if field #1 is not declared
+// as http or https URL
+func repairAdSystemDomain(line string) (string, string, bool) {
+	domain := strings.TrimSpace(line[0:strings.Index(line, ",")])
+	lower := strings.ToLower(domain)
+	if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+		return domain, "", false
+	}
+
+	host := urlHost(domain)
+	if len(host) == 0 {
+		return domain, "", false
+	}
+	return domain, host, true
+}
@@ -0,0 +1,114 @@
+package adstxt
+
+import (
+	"testing"
+)
+
+// TestParseBodyTolerant test repairing common data record format mistakes in tolerant parsing mode
+func TestParseBodyTolerant(t *testing.T) {
+	tests := []struct {
+		line     string
+		record   string
+		warnings []string
+	}{
+		{"greenadexchange.com\tXF7342\tDIRECT", "greenadexchange.com, XF7342, DIRECT", []string{CodeRepairedDelimiter}},
+		{"greenadexchange.com\t\tXF7342\t\tRESELLER\t\t12345", "greenadexchange.com, XF7342, RESELLER, 12345", []string{CodeRepairedDelimiter}},
+		{"greenadexchange.com; XF7342; DIRECT; 12345", "greenadexchange.com, XF7342, DIRECT, 12345", []string{CodeRepairedDelimiter}},
+		{"greenadexchange.com | XF7342 | direct ; key=value", "greenadexchange.com, XF7342, DIRECT; key=value", []string{CodeRepairedDelimiter}},
+		{"\"greenadexchange.com, XF7342, DIRECT\" # comment", "greenadexchange.com, XF7342, DIRECT", []string{CodeRepairedQuotes}},
+		{"'greenadexchange.com\tXF7342\tDIRECT'", "greenadexchange.com, XF7342, DIRECT", []string{CodeRepairedQuotes, CodeRepairedDelimiter}},
+		{"https://greenadexchange.com/, XF7342, DIRECT", "greenadexchange.com, XF7342, DIRECT", []string{CodeRepairedAdSystemDomain}},
+		{"HTTP://greenadexchange.com; XF7342; DIRECT", "greenadexchange.com, XF7342, DIRECT", []string{CodeRepairedDelimiter, CodeRepairedAdSystemDomain}},
+		{"greenadexchange.com, XF7342, DIRECT", "greenadexchange.com, XF7342, DIRECT", []string{}},
+		// not repaired
+		{"greenadexchange.com\tXF7342\tOTHER", "", []string{CodeUnparseableLine}},
+		{"greenadexchange.com; XF7342", "", []string{CodeUnparseableLine}},
+		{"\"subdomain=test.com\"", "", []string{CodeInvalidVariableType}},
+	}
+
+	for _, test := range tests {
+		rec, err := ParseBodyWithOptions([]byte(test.line), &ParseOptions{Registry: testRegistry, Tolerant: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		record := ""
+		if len(rec.DataRecords) == 1 {
+			record = rec.DataRecords[0].String()
+			if rec.DataRecords[0].Raw != test.line {
+				t.Errorf("Expected repaired data record raw text to be [%s] but recieved [%s]", test.line, rec.DataRecords[0].Raw)
+			}
+		}
+		if record != test.record {
+			t.Errorf("Expected [%q] to be parsed into [%s] but recieved [%s]", test.line, test.record, record)
+		}
+
+		if len(rec.Warnings) != len(test.warnings) {
+			t.Errorf("Expected [%q] to have [%d] warnings but recieved [%v]", test.line, len(test.warnings), rec.Warnings)
+			continue
+		}
+		for i, w := range rec.Warnings {
+			if w.Code != test.warnings[i] {
+				t.Errorf("Expected [%q] warning [%d] to be [%s] but recieved [%s]", test.line, i+1, test.warnings[i], w.Code)
+			}
+			if test.record != "" && w.Level != WarningSevirity {
+				t.Errorf("Expected [%q] repair warning [%s] sevirity to be [%s] but recieved [%s]", test.line, w.Code, WarningSevirity, w.Level)
+			}
+		}
+	}
+
+	// format mistakes are not repaired unless tolerant mode is requested
+	rec, _ := ParseBodyWithOptions([]byte("greenadexchange.com\tXF7342\tDIRECT"), &ParseOptions{Registry: testRegistry})
+	if len(rec.DataRecords) != 0 || len(rec.Warnings) != 1 || rec.Warnings[0].Code != CodeUnparseableLine {
+		t.Errorf("Expected tab delimited data record to be rejected when not parsing in tolerant mode")
+	}
+}
+
+// TestParseBodyTolerantInvariants test parse invariants of quirky Ads.txt files parsed in tolerant mode
+func TestParseBodyTolerantInvariants(t *testing.T) {
+	for name, b := range quirkyFiles(t) {
+		rec, err := ParseBodyWithOptions(b, &ParseOptions{Registry: testRegistry, Tolerant: true, IncludeSuppressed: true})
+		if err != nil {
+			t.Fatalf("Expected [%s] to be parsed but recieved [%s]", name, err)
+		}
+		checkRecordsInvariants(t, b, rec)
+	}
+}
+
+// TestParseBodyTolerantColumns test warnings found on repaired data record concern the columns of the original line
+func TestParseBodyTolerantColumns(t *testing.T) {
+	tests := []struct {
+		line      string
+		column    int
+		endColumn int
+	}{
+		{"\"google.com, pub-1, DIRECT\"", 14, 19},
+		{"'  google.com, pub-1, DIRECT'", 16, 21},
+		{"http://google.com/|pub-3|DIRECT", 20, 25},
+		{"google.com\t\tpub-3\tDIRECT # comment", 13, 18},
+		{"  \"https://google.com; pub-3; DIRECT\"", 24, 29},
+	}
+
+	for _, test := range tests {
+		rec, _ := ParseBodyWithOptions([]byte(test.line), &ParseOptions{Registry: testRegistry, Tolerant: true})
+		var w *Warning
+		for _, rw := range rec.Warnings {
+			if rw.Code == CodeInvalidAccountIDFormat {
+				w = rw
+			}
+		}
+		if w == nil || w.Field != 2 || w.Column != test.column || w.EndColumn != test.endColumn {
+			t.Errorf("Expected [%q] account ID warning to concern columns [%d-%d] but recieved [%v]", test.line, test.column, test.endColumn, w)
+			continue
+		}
+		if value := test.line[w.Column-1 : w.EndColumn-1]; value != w.Params["value"] {
+			t.Errorf("Expected [%q] account ID warning columns to point at the account ID but recieved [%s]", test.line, value)
+		}
+	}
+
+	// parse warnings of repaired data record concern the columns of the original line as well
+	rec, _ := ParseBodyWithOptions([]byte("\"greenadexchange.com, XF7342, OTHER\""), &ParseOptions{Registry: testRegistry, Tolerant: true})
+	if w := rec.Warnings[len(rec.Warnings)-1]; w.Code != CodeInvalidAccountType || w.Column != 31 || w.EndColumn != 36 {
+		t.Errorf("Expected invalid account type warning to concern columns [31-36] but recieved [%v]", w)
+	}
+}
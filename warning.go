@@ -1,21 +1,240 @@
 package adstxt
 
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 // Warning represent failure to parse Ads.txt line according to official ads.txt spec
 type Warning struct {
-	Index   int      `json:"index"` // Index of the line in the Ads.txt file in which warning was found
-	Text    string   `json:"txt"`   // Text of the line in the Ads.txt file in which warning was found
-	Message string   `json:"msg"`   // Warning reason
-	Level   Sevirity `json:"level"` // Sevirity level of parse warning
+	Index      int               `json:"index"`                // Index of the line in the Ads.txt file in which warning was found
+	Text       string            `json:"txt"`                  // Text of the line in the Ads.txt file in which warning was found
+	Message    string            `json:"msg"`                  // Warning reason
+	Level      Sevirity          `json:"level"`                // Sevirity level of parse warning
+	Code       string            `json:"code"`                 // Code stable machine readable identifier of the warning (see WarningCodes)
+	Field      int               `json:"field,omitempty"`      // Field number (1-based) of the data record or variable the warning concerns, 0 if not a single field
+	Column     int               `json:"column,omitempty"`     // Column (1-based) of the first character the warning concerns
+	EndColumn  int               `json:"endColumn,omitempty"`  // EndColumn (1-based) of the column following the last character the warning concerns
+	Params     map[string]string `json:"params,omitempty"`     // Params structured warning parameters (e.g. the invalid value)
+	Indexes    []int             `json:"indexes,omitempty"`    // Indexes of all the lines involved in file level warning (e.g. duplicate records)
+	Suppressed bool              `json:"suppressed,omitempty"` // Suppressed warning was suppressed by "adstxt:ignore" comment directive
 }
 
-// Sevirity of parse warning (low for moderate warning, high indicates potential erro)
+// Sevirity of parse warning, from informational notes to lines which could not be parsed at all
 type Sevirity int
 
 const (
 	// ignore first value by assigning to blank identifier
 	_ = iota
-	// LowSevirity sevirity level for parse warning (low)
-	LowSevirity Sevirity = 1 + iota // Warning indicate
-	// HighSevirity lsevirity level for parse warning (high, indicates possible error)
-	HighSevirity
+	// InfoSevirity sevirity level for informational parse warning (e.g. value is valid but not in its preferred form)
+	InfoSevirity Sevirity = iota
+	// WarningSevirity sevirity level for moderate parse warning (record is valid but might be incorrect)
+	WarningSevirity
+	// ErrorSevirity sevirity level for parse error (record is invalid and is rejected)
+	ErrorSevirity
+	// FatalSevirity sevirity level for line which could not be parsed at all
+	FatalSevirity
+)
+
+const (
+	// LowSevirity sevirity level for parse warning (low). Sevirity levels were renumbered when info, warning, error
+	// and fatal levels were introduced: LowSevirity was 1, and is now WarningSevirity (2)
+	//
+	// Deprecated: use WarningSevirity
+	LowSevirity = WarningSevirity
+	// HighSevirity sevirity level for parse warning (high, indicates possible error). Sevirity levels were renumbered
+	// when info, warning, error and fatal levels were introduced: HighSevirity was 2, and is now ErrorSevirity (3)
+	//
+	// Deprecated: use ErrorSevirity
+	HighSevirity = ErrorSevirity
+)
+
+// sevirityNames sevirity levels names, used when encoding sevirity level to JSON
+var sevirityNames = map[Sevirity]string{
+	InfoSevirity:    "info",
+	WarningSevirity: "warning",
+	ErrorSevirity:   "error",
+	FatalSevirity:   "fatal",
+}
+
+// custom "toString" method
+func (s Sevirity) String() string {
+	if name, ok := sevirityNames[s]; ok {
+		return name
+	}
+	return strconv.Itoa(int(s))
+}
+
+// MarshalText encode sevirity level as its name (e.g. "warning")
+func (s Sevirity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decode sevirity level from its name (case insensitive). Numeric values are not accepted, as sevirity
+// levels were renumbered (see LowSevirity and HighSevirity)
+func (s *Sevirity) UnmarshalText(b []byte) error {
+	text := strings.ToLower(strings.TrimSpace(string(b)))
+	for level, name := range sevirityNames {
+		if name == text {
+			*s = level
+			return nil
+		}
+	}
+	return fmt.Errorf("[%s] is not a valid sevirity level", string(b))
+}
+
+// UnmarshalJSON decode sevirity level from JSON string (sevirity name)
+func (s *Sevirity) UnmarshalJSON(b []byte) error {
+	return s.UnmarshalText(bytes.Trim(b, `"`))
+}
+
+// Warning codes: stable machine readable identifiers of parse warnings. Warning messages may be reworded
+// over time, codes will not.
+const (
+	// CodeUnparseableLine line could not be parsed as either data record or variable
+	CodeUnparseableLine = "ADSTXT_UNPARSEABLE_LINE"
+	// CodeLineTooLong line exceeds the maximum line length and was not parsed
+	CodeLineTooLong = "ADSTXT_LINE_TOO_LONG"
+	// CodeInvalidFieldCount data record does not have 3 or 4 fields
+	CodeInvalidFieldCount = "ADSTXT_INVALID_FIELD_COUNT"
+	// CodeMissingAdSystemDomain data record field #1 (domain name of the advertising system) is empty
+	CodeMissingAdSystemDomain = "ADSTXT_MISSING_AD_SYSTEM_DOMAIN"
+	// CodeInvalidAdSystemDomain data record field #1 is not a valid domain name
+	CodeInvalidAdSystemDomain = "ADSTXT_INVALID_AD_SYSTEM_DOMAIN"
+	// CodeNonNormalizedDomain data record field #1 is not in its normalized form (IDNA ASCII, lowercase, no trailing dot)
+	CodeNonNormalizedDomain = "ADSTXT_NON_NORMALIZED_DOMAIN"
+	// CodeUnknownAdSystem data record field #1 is not a known ad system domain
+	CodeUnknownAdSystem = "ADSTXT_UNKNOWN_AD_SYSTEM"
+	// CodeNonCanonicalAdSystem data record field #1 is a known ad system domain, but not its canonical domain
+	CodeNonCanonicalAdSystem = "ADSTXT_NON_CANONICAL_AD_SYSTEM"
+	// CodeMissingAccountID data record field #2 (publisher's account ID) is empty
+	CodeMissingAccountID = "ADSTXT_MISSING_ACCOUNT_ID"
+	// CodeInvalidAccountIDFormat data record field #2 does not match the account ID format of the ad system
+	CodeInvalidAccountIDFormat = "ADSTXT_INVALID_ACCOUNT_ID_FORMAT"
+	// CodeMissingAccountType data record field #3 (type of account/relationship) is empty
+	CodeMissingAccountType = "ADSTXT_MISSING_ACCOUNT_TYPE"
+	// CodeInvalidAccountType data record field #3 is neither DIRECT nor RESELLER
+	CodeInvalidAccountType = "ADSTXT_INVALID_ACCOUNT_TYPE"
+	// CodeInvalidCertAuthorityID data record field #4 (certification authority ID) is not alphanumeric
+	CodeInvalidCertAuthorityID = "ADSTXT_INVALID_CERT_AUTHORITY_ID"
+	// CodeMissingCertAuthorityID data record field #4 is missing, while the ad system published its certification authority ID
+	CodeMissingCertAuthorityID = "ADSTXT_MISSING_CERT_AUTHORITY_ID"
+	// CodeCertAuthorityIDMismatch data record field #4 does not match the certification authority ID published by the ad system
+	CodeCertAuthorityIDMismatch = "ADSTXT_CERT_AUTHORITY_ID_MISMATCH"
+	// CodeInvalidExtension data record extension data could not be parsed by the ad system ExtensionParser
+	CodeInvalidExtension = "ADSTXT_INVALID_EXTENSION"
+	// CodeRepairedQuotes data record wrapped in quotes was unquoted (tolerant parsing mode)
+	CodeRepairedQuotes = "ADSTXT_REPAIRED_QUOTES"
+	// CodeRepairedDelimiter data record fields separated by tab, semicolon or pipe were split (tolerant parsing mode)
+	CodeRepairedDelimiter = "ADSTXT_REPAIRED_DELIMITER"
+	// CodeRepairedAdSystemDomain data record field #1 declared as URL was replaced by its host name (tolerant parsing mode)
+	CodeRepairedAdSystemDomain = "ADSTXT_REPAIRED_AD_SYSTEM_DOMAIN"
+	// CodeInvalidVariableType variable type is not supported
+	CodeInvalidVariableType = "ADSTXT_INVALID_VARIABLE_TYPE"
+	// CodeMissingVariableValue variable value is empty
+	CodeMissingVariableValue = "ADSTXT_MISSING_VARIABLE_VALUE"
+	// CodeInvalidVariableValue variable value is not valid for its type (e.g. contact is not an email, URL or phone number)
+	CodeInvalidVariableValue = "ADSTXT_INVALID_VARIABLE_VALUE"
+	// CodeSubdomainOutOfScope subdomain variable is not a subdomain of the Ads.txt file root domain
+	CodeSubdomainOutOfScope = "ADSTXT_SUBDOMAIN_OUT_OF_SCOPE"
+	// CodeDuplicateRecord data record is declared more than once
+	CodeDuplicateRecord = "ADSTXT_DUPLICATE_RECORD"
+	// CodeConflictingAccountType ad system account is declared as both DIRECT and RESELLER
+	CodeConflictingAccountType = "ADSTXT_CONFLICTING_ACCOUNT_TYPE"
+	// CodeInconsistentCertAuthorityID data records of the same ad system declare different certification authority IDs
+	CodeInconsistentCertAuthorityID = "ADSTXT_INCONSISTENT_CERT_AUTHORITY_ID"
+	// CodeDuplicateVariable variable is declared more than once
+	CodeDuplicateVariable = "ADSTXT_DUPLICATE_VARIABLE"
+	// CodeNoDataRecords Ads.txt file has no valid data records
+	CodeNoDataRecords = "ADSTXT_NO_DATA_RECORDS"
 )
+
+// WarningCodes catalogue of all warning codes reported when parsing Ads.txt file, mapped to a short
+// description of each code
+var WarningCodes = map[string]string{
+	CodeUnparseableLine:         "Line could not be parsed as either data record or variable",
+	CodeLineTooLong:             "Line exceeds the maximum line length and was not parsed",
+	CodeInvalidFieldCount:       "Data record must be declared as <FIELD #1>, <FIELD #2>, <FIELD #3>, <FIELD #4> (optional)",
+	CodeMissingAdSystemDomain:   "Missing domain name of the advertising system (field #1)",
+	CodeInvalidAdSystemDomain:   "Domain name of the advertising system (field #1) is not a valid domain name",
+	CodeNonNormalizedDomain:     "Domain name of the advertising system (field #1) is not in its normalized form (IDNA ASCII, lowercase, no trailing dot)",
+	CodeUnknownAdSystem:         "Domain name of the advertising system (field #1) is not a known ad system",
+	CodeNonCanonicalAdSystem:    "Domain name of the advertising system (field #1) is not the ad system canonical domain",
+	CodeMissingAccountID:        "Missing publisher's account ID (field #2)",
+	CodeInvalidAccountIDFormat:  "Publisher's account ID (field #2) does not match the account ID format of the ad system",
+	CodeMissingAccountType:      "Missing type of account/relationship (field #3)",
+	CodeInvalidAccountType:      "Type of account/relationship (field #3) must be DIRECT or RESELLER",
+	CodeInvalidCertAuthorityID:  "Certification authority ID (field #4) is not alphanumeric",
+	CodeMissingCertAuthorityID:  "Certification authority ID (field #4) is missing, while the ad system published its certification authority ID",
+	CodeCertAuthorityIDMismatch: "Certification authority ID (field #4) does not match the certification authority ID published by the ad system",
+	CodeInvalidExtension:        "Extension data could not be parsed by the ad system extension parser",
+	CodeRepairedQuotes:          "Data record is wrapped in quotes (repaired in tolerant parsing mode)",
+	CodeRepairedDelimiter:       "Data record fields are separated by tab, semicolon or pipe instead of comma (repaired in tolerant parsing mode)",
+	CodeRepairedAdSystemDomain:  "Domain name of the advertising system (field #1) is declared as URL (repaired in tolerant parsing mode)",
+	CodeInvalidVariableType:     "Variable type is not supported",
+	CodeMissingVariableValue:    "Missing variable value",
+	CodeInvalidVariableValue:    "Variable value is not valid for its type (contact must be an email, URL or phone number, domains must be valid host names)",
+	CodeSubdomainOutOfScope:     "Subdomain variable is not a subdomain of the Ads.txt file root domain",
+
+	CodeDuplicateRecord:             "Data record is declared more than once",
+	CodeConflictingAccountType:      "Ad system account is declared as both DIRECT and RESELLER",
+	CodeInconsistentCertAuthorityID: "Data records of the same ad system declare different certification authority IDs",
+	CodeDuplicateVariable:           "Variable is declared more than once (OWNERDOMAIN may be declared only once)",
+	CodeNoDataRecords:               "Ads.txt file has no valid data records",
+}
+
+// ValidationError is returned when parsing Ads.txt file in strict mode, and errors were found in the file
+type ValidationError struct {
+	Records *Records   // Records parsed from the Ads.txt file
+	Errors  []*Warning // Errors warnings with ErrorSevirity level or above
+}
+
+// Error is the error interface implementation for the ValidationError type
+func (e *ValidationError) Error() string {
+	w := e.Errors[0]
+	switch {
+	case len(w.Indexes) > 0:
+		return fmt.Sprintf("Ads.txt file has [%d] errors, first error on lines %v: %s", len(e.Errors), w.Indexes, w.Message)
+	case w.Index > 0:
+		return fmt.Sprintf("Ads.txt file has [%d] errors, first error on line [%d]: %s", len(e.Errors), w.Index, w.Message)
+	}
+	return fmt.Sprintf("Ads.txt file has [%d] errors, first error: %s", len(e.Errors), w.Message)
+}
+
+// validateStrict return ValidationError if any of the records warnings is an error
+func validateStrict(r *Records) error {
+	errors := []*Warning{}
+	for _, w := range r.Warnings {
+		if w.Level >= ErrorSevirity {
+			errors = append(errors, w)
+		}
+	}
+
+	if len(errors) > 0 {
+		return &ValidationError{Records: r, Errors: errors}
+	}
+	return nil
+}
+
+// newWarning create new warning concerning the specified field of Ads.txt line
+func newWarning(code string, level Sevirity, f field, msg string, params map[string]string) *Warning {
+	return &Warning{
+		Code:      code,
+		Level:     level,
+		Message:   msg,
+		Field:     f.index,
+		Column:    f.column,
+		EndColumn: f.endColumn,
+		Params:    params,
+	}
+}
+
+// shift move warning column span by the specified offset, used when the warning was found on part of Ads.txt line
+func (w *Warning) shift(offset int) {
+	if w.Column > 0 {
+		w.Column += offset
+		w.EndColumn += offset
+	}
+}
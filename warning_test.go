@@ -0,0 +1,131 @@
+package adstxt
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestSevirityJsonEncode test encoding sevirity level to json as its name
+func TestSevirityJsonEncode(t *testing.T) {
+	w := Warning{Index: 1, Level: FatalSevirity, Code: CodeUnparseableLine}
+
+	j, _ := json.Marshal(w)
+	if string(j) != "{\"index\":1,\"txt\":\"\",\"msg\":\"\",\"level\":\"fatal\",\"code\":\"ADSTXT_UNPARSEABLE_LINE\"}" {
+		t.Errorf("Json encoded Warning is different than expected [%s]", string(j))
+	}
+
+	counts := map[Sevirity]int{InfoSevirity: 1, ErrorSevirity: 2}
+	j, _ = json.Marshal(counts)
+	if string(j) != "{\"error\":2,\"info\":1}" {
+		t.Errorf("Json encoded sevirity counts is different than expected [%s]", string(j))
+	}
+}
+
+// TestSevirityJsonDecode test decode sevirity level from json name
+func TestSevirityJsonDecode(t *testing.T) {
+	levels := map[string]Sevirity{
+		"{\"level\":\"info\"}":    InfoSevirity,
+		"{\"level\":\"Warning\"}": WarningSevirity,
+		"{\"level\":\"error\"}":   ErrorSevirity,
+		"{\"level\":\"FATAL\"}":   FatalSevirity,
+	}
+
+	for j, level := range levels {
+		var w Warning
+		if err := json.Unmarshal([]byte(j), &w); err != nil {
+			t.Error(err)
+		}
+		if w.Level != level {
+			t.Errorf("Expected decoded sevirity level of [%s] to be [%s] but recieved [%s]", j, level, w.Level)
+		}
+	}
+
+	// unknown names and numeric values (sevirity levels were renumbered) are not accepted
+	for _, j := range []string{"{\"level\":\"critical\"}", "{\"level\":1}", "{\"level\":\"2\"}"} {
+		var w Warning
+		if err := json.Unmarshal([]byte(j), &w); err == nil {
+			t.Errorf("Expected error when decoding sevirity level of [%s] but recieved [%s]", j, w.Level)
+		}
+	}
+}
+
+// TestSevirityJsonRoundTrip test every sevirity level is decoded back from its json encoding
+func TestSevirityJsonRoundTrip(t *testing.T) {
+	levels := []Sevirity{InfoSevirity, WarningSevirity, ErrorSevirity, FatalSevirity, LowSevirity, HighSevirity}
+
+	for _, level := range levels {
+		j, err := json.Marshal(level)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var decoded Sevirity
+		if err := json.Unmarshal(j, &decoded); err != nil || decoded != level {
+			t.Errorf("Expected sevirity level [%d] encoded as [%s] to be decoded back but recieved [%d] (%v)", level, string(j), decoded, err)
+		}
+	}
+}
+
+// TestSeverityCounts test counting Ads.txt file warnings by sevirity level
+func TestSeverityCounts(t *testing.T) {
+	b := []byte("not a valid line\ngreenadexchange.com, XF7342\nexample.com, XF7342, DIRECT\nadtech.net, XF7342, DIRECT\nadtech.net, XF7342, DIRECT, <cert>")
+	res, _ := ParseBody(b)
+
+	counts := res.SeverityCounts()
+	expected := map[Sevirity]int{FatalSevirity: 2, WarningSevirity: 1, InfoSevirity: 2}
+	for level, count := range expected {
+		if counts[level] != count {
+			t.Errorf("Expected [%d] warnings with sevirity level [%s] but recieved [%d]", count, level, counts[level])
+		}
+	}
+	if counts[ErrorSevirity] != 0 {
+		t.Errorf("Expected no warnings with sevirity level [%s] but recieved [%d]", ErrorSevirity, counts[ErrorSevirity])
+	}
+}
+
+// TestParseBodyStrict test parsing Ads.txt file in strict mode
+func TestParseBodyStrict(t *testing.T) {
+	opts := &ParseOptions{Strict: true, KeepUnknownAdSystems: true}
+
+	// warnings are accepted in strict mode
+	b := []byte("example.com, XF7342, DIRECT\ngreenadexchange.com, XF7342, DIRECT")
+	res, err := ParseBodyWithOptions(b, opts)
+	if err != nil {
+		t.Errorf("Expected no error when parsing Ads.txt file without errors in strict mode [%s]", err.Error())
+	}
+	if len(res.DataRecords) != 2 {
+		t.Errorf("Expected 2 DataRecords when parsing [%s] but recieved [%d]", string(b), len(res.DataRecords))
+	}
+
+	// errors are not
+	b = []byte("greenadexchange.com, XF7342, DIRECT\ngreenadexchange.com, XF7342, UNKNOWN")
+	_, err = ParseBodyWithOptions(b, opts)
+	if err == nil {
+		t.Fatal("Expected error when parsing Ads.txt file with errors in strict mode")
+	}
+
+	e, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected ValidationError when parsing Ads.txt file with errors in strict mode but recieved [%v]", err)
+	}
+	if len(e.Errors) != 1 || e.Errors[0].Index != 2 || e.Errors[0].Code != CodeInvalidAccountType {
+		t.Errorf("Expected single [%s] error on line #2 but recieved [%v]", CodeInvalidAccountType, e.Errors)
+	}
+	if len(e.Records.DataRecords) != 1 {
+		t.Errorf("Expected ValidationError to hold parsed DataRecords but recieved [%d]", len(e.Records.DataRecords))
+	}
+	if !strings.Contains(e.Error(), "line [2]") {
+		t.Errorf("Expected ValidationError to report the line of the first error but recieved [%s]", e.Error())
+	}
+
+	// file level errors report all the lines involved, or no line at all
+	e = &ValidationError{Errors: []*Warning{{Code: CodeDuplicateRecord, Indexes: []int{1, 3}, Message: "duplicate"}}}
+	if !strings.Contains(e.Error(), "lines [1 3]") {
+		t.Errorf("Expected ValidationError to report the lines of file level error but recieved [%s]", e.Error())
+	}
+	e = &ValidationError{Errors: []*Warning{{Code: CodeNoDataRecords, Message: "no data records"}}}
+	if strings.Contains(e.Error(), "line") {
+		t.Errorf("Expected ValidationError not to report line of file level error without lines but recieved [%s]", e.Error())
+	}
+}
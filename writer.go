@@ -0,0 +1,141 @@
+package adstxt
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	errMarshalDataRecord = "Data record [%s] on line [%d] can not be written to Ads.txt file: %s"
+	errMarshalVariable   = "Variable [%s] on line [%d] can not be written to Ads.txt file: %s"
+)
+
+// MarshalOptions control how Records are written to Ads.txt file
+type MarshalOptions struct {
+	Header       []string // Header comment lines written at the top of the file (without the comment denote)
+	OmitComments bool     // OmitComments do not write data records and variables comments
+}
+
+// Marshal return Ads.txt file content declaring the specified records: data records are written first, using
+// consistent field spacing and uppercase account type, followed by the variables. Marshal only makes sure each record
+// is written as a single line which is parsed back into the same fields (e.g. fields do not contain reserved
+// characters), records are not validated: records rejected by the validation rules (e.g. unknown variable type or ad
+// system) are written as is, and are rejected again when the returned content is parsed
+func Marshal(r *Records, opts *MarshalOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &MarshalOptions{}
+	}
+
+	var b bytes.Buffer
+	for _, h := range opts.Header {
+		if strings.ContainsAny(h, "\r\n") {
+			return nil, fmt.Errorf("Header [%s] can not be written to Ads.txt file: header must be a single line", h)
+		}
+		b.WriteString(strings.TrimSpace(commentDenote+" "+h) + "\n")
+	}
+
+	for _, dr := range r.DataRecords {
+		if err := validateDataRecordText(dr); err != nil {
+			return nil, fmt.Errorf(errMarshalDataRecord, dr.AdverterDomain, dr.Line, err.Error())
+		}
+		writeLine(&b, formatDataRecord(dr), dr.Comment, opts)
+	}
+
+	for _, v := range r.Variables {
+		if err := validateVariableText(v); err != nil {
+			return nil, fmt.Errorf(errMarshalVariable, v.Type, v.Line, err.Error())
+		}
+		writeLine(&b, strings.ToUpper(v.Type)+"="+strings.TrimSpace(v.Value), v.Comment, opts)
+	}
+
+	return b.Bytes(), nil
+}
+
+// WriteTo write records to w as Ads.txt file, using the default marshal options. WriteTo is the io.WriterTo
+// interface implementation for the Records type
+func (r *Records) WriteTo(w io.Writer) (int64, error) {
+	b, err := Marshal(r, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(b)
+	return int64(n), err
+}
+
+// formatDataRecord return canonical Ads.txt declaration of data record
+func formatDataRecord(r *DataRecord) string {
+	c := *r
+	normalizeDataRecordText(&c)
+	return c.String()
+}
+
+// normalizeDataRecordText trim data record fields white spaces and uppercase account type, as they are parsed back
+// from Ads.txt file
+func normalizeDataRecordText(r *DataRecord) {
+	r.AccountType = strings.ToUpper(strings.TrimSpace(r.AccountType))
+	r.AdverterDomain = strings.TrimSpace(r.AdverterDomain)
+	r.PublisherAccountID = strings.TrimSpace(r.PublisherAccountID)
+	r.CertAuthorityID = strings.TrimSpace(r.CertAuthorityID)
+	r.Extensions = strings.TrimSpace(r.Extensions)
+}
+
+// writeLine write single Ads.txt line, followed by its comment (unless comments are omitted)
+func writeLine(b *bytes.Buffer, line string, comment string, opts *MarshalOptions) {
+	b.WriteString(line)
+	if len(comment) > 0 && !opts.OmitComments {
+		b.WriteString(" " + commentDenote + " " + comment)
+	}
+	b.WriteString("\n")
+}
+
+// validateDataRecordText make sure data record fields can be written to Ads.txt file and parsed back as is
+func validateDataRecordText(r *DataRecord) error {
+	fields := []string{r.AdverterDomain, r.PublisherAccountID, r.AccountType}
+	if len(r.CertAuthorityID) > 0 {
+		fields = append(fields, r.CertAuthorityID)
+	}
+
+	for i, f := range fields {
+		if len(strings.TrimSpace(f)) == 0 {
+			return fmt.Errorf("field #%d is empty", i+1)
+		}
+		if strings.ContainsAny(f, ","+extensionDenote+commentDenote+"\r\n") {
+			return fmt.Errorf("field #%d [%s] contains reserved character", i+1, f)
+		}
+	}
+
+	t := strings.ToUpper(strings.TrimSpace(r.AccountType))
+	if t != accountTypeDirect && t != accountTypeReseller {
+		return fmt.Errorf("[%s] is not a valid account type", r.AccountType)
+	}
+
+	if strings.ContainsAny(r.Extensions, commentDenote+"\r\n") {
+		return fmt.Errorf("extension [%s] contains reserved character", r.Extensions)
+	}
+	if !isDataRecord(formatDataRecord(r)) {
+		return fmt.Errorf("fields would be parsed as variable")
+	}
+	return validateCommentText(r.Comment)
+}
+
+// validateVariableText make sure variable can be written to Ads.txt file and parsed back as is
+func validateVariableText(v *Variable) error {
+	if len(strings.TrimSpace(v.Type)) == 0 || strings.ContainsAny(v.Type, "=,"+commentDenote+"\r\n") {
+		return fmt.Errorf("[%s] is not a valid variable type", v.Type)
+	}
+	if strings.ContainsAny(v.Value, commentDenote+"\r\n") {
+		return fmt.Errorf("value [%s] contains reserved character", v.Value)
+	}
+	return validateCommentText(v.Comment)
+}
+
+// validateCommentText make sure comment is a single line
+func validateCommentText(comment string) error {
+	if strings.ContainsAny(comment, "\r\n") {
+		return fmt.Errorf("comment [%s] must be a single line", comment)
+	}
+	return nil
+}
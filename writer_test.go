@@ -0,0 +1,112 @@
+package adstxt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestMarshalRoundTrip test that parsing marshaled records yields the same records
+func TestMarshalRoundTrip(t *testing.T) {
+	body := "contact=adops@example.com\n" +
+		"  google.com ,pub-1234,direct,f08c47fec0942fa0 # display\n" +
+		"appnexus.com,5678,Reseller;custom ext\n" +
+		"# just a comment\n" +
+		"OWNERDOMAIN=example.com\n" +
+		"contact=https://example.com/contact?team=ads # ops\n"
+
+	r, _ := ParseBody([]byte(body))
+	b, err := Marshal(r, &MarshalOptions{Header: []string{"ads.txt file for example.com"}})
+	if err != nil {
+		t.Fatalf("Failed to marshal records: %s", err)
+	}
+
+	expected := "# ads.txt file for example.com\n" +
+		"google.com, pub-1234, DIRECT, f08c47fec0942fa0 # display\n" +
+		"appnexus.com, 5678, RESELLER; custom ext\n" +
+		"CONTACT=adops@example.com\n" +
+		"OWNERDOMAIN=example.com\n" +
+		"CONTACT=https://example.com/contact?team=ads # ops\n"
+	if string(b) != expected {
+		t.Errorf("Expected records to be marshaled as [%q] but recieved [%q]", expected, string(b))
+	}
+
+	parsed, _ := ParseBody(b)
+	if len(parsed.DataRecords) != len(r.DataRecords) || len(parsed.Variables) != len(r.Variables) {
+		t.Fatalf("Expected [%d] data records and [%d] variables but recieved [%d] and [%d]",
+			len(r.DataRecords), len(r.Variables), len(parsed.DataRecords), len(parsed.Variables))
+	}
+	for i, dr := range r.DataRecords {
+		p := parsed.DataRecords[i]
+		if p.String() != dr.String() || p.Comment != dr.Comment {
+			t.Errorf("Expected data record [%s] but recieved [%s]", dr, p)
+		}
+	}
+	for i, v := range r.Variables {
+		if p := parsed.Variables[i]; p.Type != v.Type || p.Value != v.Value {
+			t.Errorf("Expected variable [%s] but recieved [%s]", v, p)
+		}
+	}
+}
+
+// TestMarshalRoundTripExtensions test that data records with <KEY>=<VALUE> extension data are parsed back as is
+func TestMarshalRoundTripExtensions(t *testing.T) {
+	r := &Records{DataRecords: []*DataRecord{
+		{AdverterDomain: "google.com", PublisherAccountID: "pub-0000000000000000", AccountType: "DIRECT", CertAuthorityID: "f08c47fec0942fa0", Extensions: "a=1;b=2;c=3;d=4;e=5;f=6"},
+		{AdverterDomain: "appnexus.com", PublisherAccountID: "5678", AccountType: "RESELLER", Extensions: "region=us; format=video; size=300x250; floor=1.5; deal=a=b; tag=x"},
+	}}
+
+	b, err := Marshal(r, nil)
+	if err != nil {
+		t.Fatalf("Failed to marshal records: %s", err)
+	}
+
+	parsed, err := ParseBody(b)
+	if err != nil || len(parsed.DataRecords) != len(r.DataRecords) {
+		t.Fatalf("Expected [%d] data records when parsing [%q] but recieved [%v] (%v)", len(r.DataRecords), string(b), parsed, err)
+	}
+	for i, dr := range r.DataRecords {
+		if p := parsed.DataRecords[i]; p.String() != dr.String() || p.Extensions != dr.Extensions {
+			t.Errorf("Expected data record [%s] but recieved [%s]", dr, p)
+		}
+	}
+}
+
+// TestMarshalInvalidRecords test that records which can not be parsed back are not written
+func TestMarshalInvalidRecords(t *testing.T) {
+	tests := []*Records{
+		{DataRecords: []*DataRecord{{AdverterDomain: "google.com", PublisherAccountID: "pub-1,234", AccountType: "DIRECT"}}},
+		{DataRecords: []*DataRecord{{AdverterDomain: "google.com", PublisherAccountID: "", AccountType: "DIRECT"}}},
+		{DataRecords: []*DataRecord{{AdverterDomain: "google.com", PublisherAccountID: "pub-1234", AccountType: "PARTNER"}}},
+		{DataRecords: []*DataRecord{{AdverterDomain: "google.com", PublisherAccountID: "pub-1234", AccountType: "DIRECT", Comment: "two\nlines"}}},
+		{DataRecords: []*DataRecord{{AdverterDomain: "contact=google.com", PublisherAccountID: "pub-1234", AccountType: "DIRECT"}}},
+		{DataRecords: []*DataRecord{{AdverterDomain: "google.com", PublisherAccountID: "a=b=c=d=e=f=g", AccountType: "DIRECT"}}},
+		{Variables: []*Variable{{Type: "contact=", Value: "ads@example.com"}}},
+		{Variables: []*Variable{{Type: "contact", Value: "ads@example.com # ops"}}},
+	}
+
+	for _, r := range tests {
+		if b, err := Marshal(r, nil); err == nil {
+			t.Errorf("Expected error when marshaling invalid records but recieved [%q]", string(b))
+		}
+	}
+}
+
+// TestRecordsWriteTo test writing records as Ads.txt file
+func TestRecordsWriteTo(t *testing.T) {
+	r := &Records{
+		DataRecords: []*DataRecord{{AdverterDomain: "google.com", PublisherAccountID: "pub-1234", AccountType: "direct", Comment: "display"}},
+		Variables:   []*Variable{{Type: "contact", Value: "adops@example.com"}},
+	}
+
+	var b bytes.Buffer
+	n, err := r.WriteTo(&b)
+	expected := "google.com, pub-1234, DIRECT # display\nCONTACT=adops@example.com\n"
+	if err != nil || n != int64(len(expected)) || b.String() != expected {
+		t.Errorf("Expected records to be written as [%q] but recieved [%q] (%v)", expected, b.String(), err)
+	}
+
+	if out, _ := Marshal(r, &MarshalOptions{OmitComments: true}); strings.Contains(string(out), "display") {
+		t.Errorf("Expected comments to be omitted but recieved [%q]", string(out))
+	}
+}